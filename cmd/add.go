@@ -0,0 +1,104 @@
+// Copyright © 2023 Nik Ogura <nik.ogura@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/nikogura/boilerplate/pkg/boilerplate"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+var addDryRun bool    //nolint:gochecknoglobals // cobra command flag
+var addSkipHooks bool //nolint:gochecknoglobals // cobra command flag
+
+// addCmd represents the add command.
+var addCmd = &cobra.Command{ //nolint:gochecknoglobals // cobra command definition
+	Use:   "add [feature] [project dir]",
+	Short: "Adds a feature to an existing project.",
+	Long: `
+Adds a feature, such as a metrics endpoint or a Helm chart, to a project that has already been generated.
+
+The feature's files are rendered with the answers recorded in the project's .boilerplate.json manifest.  Projects without a manifest get the basics from their go.mod: the module path, the project name, and the Go version.
+
+Besides adding files, a feature may hook itself into the project's existing code, e.g. registering a subcommand with rootCmd in cmd/root.go, or a route with the mux in the server.  These edits are made to the parsed Go source, so they survive reformatting and comments, and aren't repeated if already there.  Modules the new code needs are added to go.mod, and 'go mod tidy' is run unless you pass --skip-hooks.
+
+Nothing is written if any of the feature's files already exist, or an anchor it needs is missing.  --dry-run shows what would change without writing anything.
+
+Run without arguments to list the features.  If you omit the project directory, it defaults to the CWD.
+`,
+	Args: cobra.MaximumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Printf("Features:\n")
+			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			for _, f := range boilerplate.Features() {
+				types := "any"
+				if len(f.Types) > 0 {
+					types = strings.Join(f.Types, ", ")
+				}
+				fmt.Fprintf(tw, "  %s\t%s\t(%s)\n", f.Name, f.Description, types)
+			}
+			_ = tw.Flush()
+			return
+		}
+
+		projDir := "."
+		if len(args) > 1 {
+			projDir = args[1]
+		}
+
+		res, err := boilerplate.AddFeature(cmd.Context(), boilerplate.AddOptions{
+			Feature:    args[0],
+			ProjectDir: projDir,
+			DryRun:     addDryRun,
+			Hooks:      !addSkipHooks,
+		})
+		if err != nil && len(res.Hooks) == 0 {
+			log.Fatalf("failed to add feature: %v", err)
+		}
+
+		printUpdated("Added", res.Added)
+		printUpdated("Patched", res.Patched)
+		printUpdated("Required", res.Required)
+
+		for _, h := range res.Hooks {
+			if h.Err != nil {
+				fmt.Printf("  %-7s %s: %v\n", h.Status, h.Name, h.Err)
+				continue
+			}
+			fmt.Printf("  %-7s %s\n", h.Status, h.Name)
+		}
+		if err != nil {
+			log.Fatalf("failed to finish adding feature: %v", err)
+		}
+
+		if addDryRun {
+			fmt.Printf("Dry run: feature %s not added\n", res.Feature)
+			return
+		}
+
+		fmt.Printf("Feature %s added\n", res.Feature)
+	},
+}
+
+func init() { //nolint:gochecknoinits // cobra command registration
+	RootCmd.AddCommand(addCmd)
+	addCmd.Flags().BoolVar(&addDryRun, "dry-run", false, "Show what would change without writing anything")
+	addCmd.Flags().BoolVar(&addSkipHooks, "skip-hooks", false, "Don't run the feature's hooks, e.g. go mod tidy")
+}
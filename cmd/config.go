@@ -0,0 +1,125 @@
+// Copyright © 2023 Nik Ogura <nik.ogura@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/nikogura/boilerplate/pkg/boilerplate"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"text/tabwriter"
+)
+
+var configOutput string //nolint:gochecknoglobals // cobra command flag
+
+// configCmd represents the config command.
+var configCmd = &cobra.Command{ //nolint:gochecknoglobals // cobra command definition
+	Use:   "config",
+	Short: "Manages your default answers.",
+	Long: `
+Manages your default answers to boilerplate's questions, e.g. your name and email, or your organization's DBT repository.
+
+Defaults are kept in ~/.config/boilerplate/config.yaml (or $XDG_CONFIG_HOME/boilerplate/config.yaml, or $BOILERPLATE_CONFIG), keyed by prompt name.  They can also be set with BOILERPLATE_* environment variables named for a built-in prompt in SCREAMING_SNAKE case, e.g. BOILERPLATE_MAINTAINER_NAME, which win over the file.  Your name and email are taken from git config if not set otherwise.
+
+A default may be a template over earlier answers, e.g.
+
+    boilerplate config set ProjectPackage 'github.com/myorg/{{.ProjectName}}'
+`,
+}
+
+// configSetCmd represents the config set command.
+var configSetCmd = &cobra.Command{ //nolint:gochecknoglobals // cobra command definition
+	Use:   "set <key> <value>",
+	Short: "Sets a default in the config file.  An empty value removes it.",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := userConfigPath()
+
+		err := boilerplate.SetUserConfigValue(path, args[0], args[1])
+		if err != nil {
+			log.Fatalf("failed to set %s: %v", args[0], err)
+		}
+	},
+}
+
+// configGetCmd represents the config get command.
+var configGetCmd = &cobra.Command{ //nolint:gochecknoglobals // cobra command definition
+	Use:   "get <key>",
+	Short: "Shows the default in effect for a prompt.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		defs := loadUserDefaults()
+
+		def, ok := defs.Lookup(boilerplate.ParamPrompt(args[0]))
+		if !ok {
+			fmt.Fprintf(os.Stderr, "no default for %s\n", args[0])
+			os.Exit(1)
+		}
+
+		if configOutput == "json" {
+			printJSON(def)
+			return
+		}
+
+		fmt.Println(def.Value)
+	},
+}
+
+// configListCmd represents the config list command.
+var configListCmd = &cobra.Command{ //nolint:gochecknoglobals // cobra command definition
+	Use:   "list",
+	Short: "Lists the defaults in effect, and where each comes from.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		defs := loadUserDefaults()
+
+		if configOutput == "json" {
+			printJSON(defs)
+			return
+		}
+
+		fmt.Printf("Config file: %s\n\n", userConfigPath())
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, k := range defs.Keys() {
+			fmt.Fprintf(tw, "  %s\t%s\t(%s)\n", k, defs[k].Value, defs[k].Source)
+		}
+		_ = tw.Flush()
+	},
+}
+
+func userConfigPath() string {
+	path, err := boilerplate.UserConfigPath()
+	if err != nil {
+		log.Fatalf("failed to find user config: %v", err)
+	}
+
+	return path
+}
+
+func loadUserDefaults() boilerplate.UserDefaults {
+	defs, err := boilerplate.LoadUserDefaults()
+	if err != nil {
+		log.Fatalf("failed to load user defaults: %v", err)
+	}
+
+	return defs
+}
+
+func init() { //nolint:gochecknoinits // cobra command registration
+	RootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configSetCmd, configGetCmd, configListCmd)
+	configCmd.PersistentFlags().StringVarP(&configOutput, "output", "o", "text", "Output format: text or json")
+}
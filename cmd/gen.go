@@ -15,52 +15,46 @@
 package cmd
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"github.com/nikogura/boilerplate/pkg/boilerplate"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"io"
 	"log"
 	"os"
-	"strconv"
-	"strings"
+	"path/filepath"
 )
 
-var projectType string //nolint:gochecknoglobals // cobra command flag
-var destDir string     //nolint:gochecknoglobals // cobra command flag
+var projectType string  //nolint:gochecknoglobals // cobra command flag
+var destDir string      //nolint:gochecknoglobals // cobra command flag
+var valuesFile string   //nolint:gochecknoglobals // cobra command flag
+var setValues []string  //nolint:gochecknoglobals // cobra command flag
+var noPrompt bool       //nolint:gochecknoglobals // cobra command flag
+var dryRun bool         //nolint:gochecknoglobals // cobra command flag
+var showDiff bool       //nolint:gochecknoglobals // cobra command flag
+var onConflict string   //nolint:gochecknoglobals // cobra command flag
+var skipHooks bool      //nolint:gochecknoglobals // cobra command flag
+var allowCommands bool  //nolint:gochecknoglobals // cobra command flag
+var verify bool         //nolint:gochecknoglobals // cobra command flag
+var outputFormat string //nolint:gochecknoglobals // cobra command flag
+var outputPath string   //nolint:gochecknoglobals // cobra command flag
 
-// promptForProjectType prompts the user to select a project type from available options.
-func promptForProjectType() string {
-	validTypes := boilerplate.ValidProjectTypes()
+// outputDir is the --output-format that writes the project to a directory rather than an archive.
+const outputDir = "dir"
 
-	fmt.Println("Available project types:")
-	for i, pType := range validTypes {
-		fmt.Printf("  %d. %s\n", i+1, pType)
+// promptForProjectType prompts the user to select a project type from available options.
+func promptForProjectType(prompter boilerplate.Prompter) string {
+	choice, err := prompter.Prompt("ProjectType", boilerplate.Prompt{
+		PromptMsg: "Please select a project type",
+		Kind:      boilerplate.PromptChoice,
+		Choices:   boilerplate.ValidProjectTypes(),
+	})
+	if err != nil {
+		log.Fatalf("failed to select a project type: %v", err)
 	}
 
-	reader := bufio.NewReader(os.Stdin)
-	for {
-		fmt.Print("Please select a project type (number): ")
-		input, err := reader.ReadString('\n')
-		if err != nil {
-			fmt.Printf("Error reading input: %v\n", err)
-			continue
-		}
-
-		input = strings.TrimSpace(input)
-		choice, err := strconv.Atoi(input)
-		if err != nil {
-			fmt.Println("Please enter a valid number.")
-			continue
-		}
-
-		if choice < 1 || choice > len(validTypes) {
-			fmt.Printf("Please enter a number between 1 and %d.\n", len(validTypes))
-			continue
-		}
-
-		return validTypes[choice-1]
-	}
+	return choice
 }
 
 // genCmd represents the create command.
@@ -72,27 +66,93 @@ Creates a new code project based on an embedded template.
 
 When run, it will ask you for a project name, and description.  It will also ask you for the name and email address of the author.  This information is used to generate all the boilerplate files and such.
 
-Creates a skeleton project based on one of several project types.  Current supported types are:
-
-cobra   -   A project based on the excellent Cobra CLI framework.
-headless-service    -   A project implementing a standalone headless service useful for implementing APIs and the like.
-spa     -   A project based on React, designed to be built as a self-contained single page application.
+Creates a skeleton project based on one of several project types.  Run 'boilerplate types' to list them, and 'boilerplate types describe <type>' to see what a type asks and generates.
 
 Each project is set up so it can be built, and provides CI workflows for both DBT tools as well as Github actions.
 
 You can specify the project type on the command line, or be prompted.  If you omit the destination directory, it defaults to the CWD.
 
+Answers can be supplied up front with a YAML or JSON values file (--values) and individual Key=Value overrides (--set), which win over the file.  Anything not supplied is prompted for.  With --no-prompt, missing answers fall back to their defaults, and all invalid or missing answers are reported at once instead of prompting.
+
+	boilerplate gen -t cobra --values answers.yaml --set ProjectName=mytool --no-prompt
+
+Files that already exist in the destination are never silently replaced.  By default generation fails up front, listing every existing file, before anything is written.  Use --on-conflict to skip existing files, overwrite them, or back them up to a .bak sidecar before writing.
+
+Templates normally come from those embedded in boilerplate.  To use your own, point --template-dir at a local directory, or --template-repo (and optionally --template-ref) at a git repository, holding a tree of the same shape as the embedded ones, i.e. {{.ProjectName}}/...  If the tree has a boilerplate.yaml at its root, that declares the project type and the questions asked; otherwise --type selects one of the built-in sets of questions.
+
+To preview without writing anything, --dry-run lists every file that would be written, its size, and what would be done with it: create, or for a file already there, overwrite, skip or backup as --on-conflict says, or conflict if generation would fail on it.  --diff shows a unified diff of the rendered project against what already exists in the destination directory.
+
+--verify checks the generated Go code before going any further: every file must parse, imports must resolve to the project's own packages, the standard library, or a module in go.mod, packages must type check as far as their imports can be loaded, and go.mod must declare the project package.  Problems are reported by file and line.
+
+Once the project is written, the project type's hooks run, each reporting whether it succeeded, failed or was skipped.  The built-in types format the Go code, run 'go mod tidy' if go is installed, commit the project to a new git repository (unless it is already inside one), and install pre-commit-hook.sh as the git pre-commit hook.  --skip-hooks leaves all that out.
+
+Templates with a boilerplate.yaml can also declare hooks that run shell commands.  These are skipped unless you pass --allow-command-hooks, since they come from the templates rather than from you, and each command is printed before it runs.
+
+--output-format tar, tgz or zip packs the project into an archive instead, rendered entirely in memory, with everything under the project name and file modes kept.  The hooks that set up a git repository don't run for archives.  --output names the archive, which otherwise goes in the destination directory, or - writes it to stdout, with everything else on stderr:
+
+	boilerplate gen -t cobra --values answers.yaml --no-prompt --output-format tgz --output - | tar xz
+
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
 		var err error
 
+		var archive boilerplate.ArchiveFormat
+		if outputFormat != outputDir {
+			archive, err = boilerplate.ParseArchiveFormat(outputFormat)
+			if err != nil {
+				log.Fatalf("invalid output format %q: must be one of dir, tar, tgz, zip", outputFormat)
+			}
+			if dryRun || showDiff {
+				log.Fatalf("--dry-run and --diff only apply to --output-format dir")
+			}
+		} else if outputPath != "" {
+			log.Fatalf("--output only applies to archives; use --dest-dir for a directory")
+		}
+
+		// With the project on stdout, everything else goes to stderr.
+		status := io.Writer(os.Stdout)
+		if outputPath == "-" {
+			status = os.Stderr
+		}
+
+		var prompter boilerplate.Prompter
+		if !noPrompt {
+			rp := boilerplate.NewReaderPrompter(os.Stdin)
+			rp.Out = status
+			prompter = rp
+		}
+
+		templFs, source, cleanup, err := openTemplateSource()
+		if err != nil {
+			log.Fatalf("failed to load templates: %v", err)
+		}
+		defer cleanup()
+
+		// A template tree with a boilerplate.yaml declares its own project type.
+		var spec *boilerplate.TemplateSpec
+		if templFs != nil {
+			s, specErr := boilerplate.LoadTemplateSpec(templFs, ".")
+			switch {
+			case specErr == nil:
+				spec = &s
+				if projectType != "" && projectType != spec.Name {
+					log.Fatalf("templates at %s are for project type %q, not %q", source, spec.Name, projectType)
+				}
+				projectType = spec.Name
+			case !errors.Is(specErr, boilerplate.ErrNoTemplateSpec):
+				log.Fatalf("failed to load template spec: %v", specErr)
+			}
+		}
+
 		// Determine project type
 		if projectType == "" {
 			if len(args) > 0 {
 				projectType = args[0]
+			} else if noPrompt {
+				log.Fatalf("a project type is required when --no-prompt is set")
 			} else {
 				// Prompt user for project type selection
-				projectType = promptForProjectType()
+				projectType = promptForProjectType(prompter)
 			}
 		}
 
@@ -103,38 +163,165 @@ You can specify the project type on the command line, or be prompted.  If you om
 			}
 		}
 
-		if !boilerplate.IsValidProjectType(projectType) {
+		policy, err := boilerplate.ParseConflictPolicy(onConflict)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+
+		if spec == nil && !boilerplate.IsValidProjectType(projectType) {
 			log.Fatalf("invalid project type: %q. Valid project types are: %s", projectType, boilerplate.ValidProjectTypes())
 		}
 
-		fmt.Printf("Creating new project of type %q\n", projectType)
+		fmt.Fprintf(status, "Creating new project of type %q\n", projectType)
 
-		prompts, err := boilerplate.PromptsForProject(projectType)
+		vals := make(map[string]string)
+		if valuesFile != "" {
+			vals, err = boilerplate.LoadValuesFile(valuesFile)
+			if err != nil {
+				log.Fatalf("failed to load values: %v", err)
+			}
+		}
+
+		overrides, err := boilerplate.ParseSetValues(setValues)
 		if err != nil {
-			log.Fatalf("failed to get prompts for project type %s: %v", projectType, err)
+			log.Fatalf("failed to parse value overrides: %v", err)
 		}
 
-		datamap, err := prompts.AsMap()
+		userDefaults, err := boilerplate.LoadUserDefaults()
 		if err != nil {
-			log.Fatalf("failed to export params as map: %v", err)
+			log.Fatalf("failed to load user defaults: %v", err)
+		}
+
+		opts := boilerplate.Options{
+			Type:       projectType,
+			Templates:  templFs,
+			Source:     source,
+			Values:     boilerplate.MergeValues(vals, overrides),
+			Defaults:   userDefaults.Values(),
+			OutFs:      afero.NewOsFs(),
+			Dest:       destDir,
+			OnConflict: policy,
+			Verify:     verify,
+			Prompter:   prompter,
+			Hooks:      !skipHooks,
+
+			AllowCommandHooks: allowCommands,
+			Log:               status,
+		}
+		if archive != "" {
+			// Archives are built in memory, and never touch the destination.
+			opts.OutFs = afero.NewMemMapFs()
+			opts.Dest = "/"
+			opts.Archive = true
+		}
+
+		if dryRun || showDiff {
+			wr, prepErr := boilerplate.PrepareWriter(cmd.Context(), opts)
+			if prepErr != nil {
+				log.Fatalf("failed to prepare templated project: %v", prepErr)
+			}
+
+			if showDiff {
+				diff, diffErr := wr.Diff(destDir)
+				if diffErr != nil {
+					log.Fatalf("failed to diff templated project: %v", diffErr)
+				}
+
+				fmt.Print(diff)
+				return
+			}
+
+			plan, planErr := wr.Plan(destDir)
+			if planErr != nil {
+				log.Fatalf("failed to plan templated project: %v", planErr)
+			}
+
+			for _, pf := range plan {
+				fmt.Printf("  %-9s %8d  %s\n", pf.Action, pf.Size, pf.TemplPath)
+			}
+			return
 		}
 
-		wr, err := boilerplate.NewTmplWriter(afero.NewOsFs(), projectType, datamap)
+		res, err := boilerplate.Generate(cmd.Context(), opts)
+		if err != nil && len(res.Hooks) == 0 {
+			log.Fatalf("failed to create templated project: %v", err)
+		}
+
+		for _, h := range res.Hooks {
+			if errors.Is(h.Err, boilerplate.ErrCommandHooksNotAllowed) {
+				fmt.Fprintf(status, "  %-7s %s: %v (pass --allow-command-hooks to run it)\n", h.Status, h.Name, h.Err)
+				continue
+			}
+			if h.Err != nil {
+				fmt.Fprintf(status, "  %-7s %s: %v\n", h.Status, h.Name, h.Err)
+				continue
+			}
+			fmt.Fprintf(status, "  %-7s %s\n", h.Status, h.Name)
+		}
 		if err != nil {
-			log.Fatalf("failed to create template writer: %v", err)
+			log.Fatalf("failed to finish templated project: %v", err)
+		}
+
+		if archive == "" {
+			fmt.Fprintf(status, "New project created in ./%s\n", res.Values["ProjectName"])
+			return
+		}
+
+		out := outputPath
+		if out == "" {
+			out = filepath.Join(destDir, filepath.Base(res.ProjectDir)+archive.Ext())
 		}
 
-		err = wr.BuildProject(destDir)
+		err = writeArchiveOutput(out, opts.OutFs, res.ProjectDir, archive)
 		if err != nil {
-			log.Fatalf("failed to create templated project: %v", err)
+			log.Fatalf("failed to write project archive: %v", err)
 		}
 
-		fmt.Printf("New project created in ./%s\n", datamap["ProjectName"])
+		if out != "-" {
+			fmt.Fprintf(status, "New project written to %s\n", out)
+		}
 	},
 }
 
+// writeArchiveOutput packs the project at dir in afs into an archive at path, or on stdout if path is "-".  A
+// partly written archive is removed.
+func writeArchiveOutput(path string, afs afero.Fs, dir string, format boilerplate.ArchiveFormat) (err error) {
+	if path == "-" {
+		return boilerplate.WriteArchive(os.Stdout, afs, dir, format)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = boilerplate.WriteArchive(f, afs, dir, format)
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+	}
+
+	return err
+}
+
 func init() { //nolint:gochecknoinits // cobra command registration
 	RootCmd.AddCommand(genCmd)
 	genCmd.Flags().StringVarP(&projectType, "type", "t", "", "Project Type (if not specified, you'll be prompted to select)")
 	genCmd.Flags().StringVarP(&destDir, "dest-dir", "d", "", "Destination Directory (Defaults to CWD)")
+	genCmd.Flags().StringVarP(&valuesFile, "values", "f", "", "YAML or JSON file of answers keyed by prompt name")
+	genCmd.Flags().StringArrayVar(&setValues, "set", nil, "Answer override in the form Key=Value (repeatable)")
+	genCmd.Flags().BoolVar(&noPrompt, "no-prompt", false, "Never prompt. Missing answers fall back to their defaults")
+	genCmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the files that would be written without writing them")
+	genCmd.Flags().BoolVar(&showDiff, "diff", false, "Show a unified diff against the destination directory without writing")
+	genCmd.MarkFlagsMutuallyExclusive("dry-run", "diff")
+	addTemplateSourceFlags(genCmd)
+	genCmd.Flags().BoolVar(&verify, "verify", false, "Parse and type check the generated Go code before reporting success")
+	genCmd.Flags().BoolVar(&skipHooks, "skip-hooks", false, "Don't run the project type's post-generation hooks")
+	genCmd.Flags().BoolVar(&allowCommands, "allow-command-hooks", false, "Run hooks declared by the templates that run shell commands")
+	genCmd.Flags().StringVar(&outputFormat, "output-format", outputDir, "Write the project to a directory, or as a tar, tgz or zip archive")
+	genCmd.Flags().StringVarP(&outputPath, "output", "o", "", "Archive to write, or - for stdout (defaults to <ProjectName>.<ext> in the destination directory)")
+	genCmd.Flags().StringVar(&onConflict, "on-conflict", string(boilerplate.OnConflictFail), "What to do with existing files: fail, skip, overwrite or backup")
 }
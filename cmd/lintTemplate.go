@@ -0,0 +1,106 @@
+// Copyright © 2023 Nik Ogura <nik.ogura@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/nikogura/boilerplate/pkg/boilerplate"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var lintTemplateType string   //nolint:gochecknoglobals // cobra command flag
+var lintTemplateOutput string //nolint:gochecknoglobals // cobra command flag
+
+// lintTemplateCmd represents the lint-template command.
+var lintTemplateCmd = &cobra.Command{ //nolint:gochecknoglobals // cobra command definition
+	Use:   "lint-template [template dir]",
+	Short: "Checks a template tree for mistakes.",
+	Long: `
+Checks a template tree for mistakes, without generating anything.
+
+Every path and file in the tree is parsed, and the values each uses are listed.  Problems found are:
+
+  - templates that don't parse
+  - values that no question or derived value provides, such as a typo in a field name
+  - questions that no file, file rule or derived value uses
+  - Go imports that the generated go.mod doesn't require
+
+The last is found by rendering the project in memory with each question's default.  The command exits non-zero if there are any problems.
+
+If the tree has no boilerplate.yaml, --type selects one of the built-in sets of questions, as with 'test-template'.  With no template dir, --type lints the built-in templates for that type.
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var pt boilerplate.ProjectType
+		switch {
+		case len(args) == 1:
+			dir, err := filepath.Abs(args[0])
+			if err != nil {
+				log.Fatalf("failed to resolve %s: %v", args[0], err)
+			}
+			pt = templateDirType(dir, lintTemplateType)
+		case lintTemplateType == "":
+			log.Fatalf("give a template dir, or --type to lint a built-in type")
+		default:
+			var ok bool
+			pt, ok = boilerplate.LookupProjectType(lintTemplateType)
+			if !ok {
+				log.Fatalf("invalid project type: %q. Valid project types are: %s", lintTemplateType, boilerplate.ValidProjectTypes())
+			}
+		}
+
+		lint, err := boilerplate.LintTemplate(pt)
+		if err != nil {
+			log.Fatalf("failed to lint %s: %v", pt.Name, err)
+		}
+
+		if lintTemplateOutput == "json" {
+			printJSON(lint)
+		} else {
+			files := make([]string, 0, len(lint.Vars))
+			for f := range lint.Vars {
+				files = append(files, f)
+			}
+			sort.Strings(files)
+
+			fmt.Printf("Values used:\n")
+			for _, f := range files {
+				fmt.Printf("  %s: %s\n", f, strings.Join(lint.Vars[f], ", "))
+			}
+
+			if len(lint.Problems) > 0 {
+				fmt.Printf("\nProblems:\n")
+				for _, p := range lint.Problems {
+					fmt.Printf("  %s\n", p)
+				}
+			}
+		}
+
+		if len(lint.Problems) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() { //nolint:gochecknoinits // cobra command registration
+	RootCmd.AddCommand(lintTemplateCmd)
+	lintTemplateCmd.Flags().StringVarP(&lintTemplateType, "type", "t", "", "Built-in project type to lint, or whose questions to use if the tree has no boilerplate.yaml")
+	lintTemplateCmd.Flags().StringVarP(&lintTemplateOutput, "output", "o", "text", "Output format: text or json")
+}
@@ -0,0 +1,75 @@
+// Copyright © 2023 Nik Ogura <nik.ogura@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/nikogura/boilerplate/pkg/boilerplate"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var serveAddress string //nolint:gochecknoglobals // cobra command flag
+
+// serveCmd represents the serve command.
+var serveCmd = &cobra.Command{ //nolint:gochecknoglobals // cobra command definition
+	Use:   "serve",
+	Short: "Serves project generation over HTTP.",
+	Long: `
+Serves project generation over HTTP, so projects can be generated from a form without installing boilerplate.
+
+	GET  /types      the project types, with the questions each asks, as 'boilerplate types describe' does
+	POST /generate   a project, as a .tar.gz or .zip
+	GET  /metrics    Prometheus metrics
+	GET  /healthz    liveness probe
+	GET  /readyz     readiness probe
+
+A generate request is JSON naming the project type, the answers keyed by prompt, and optionally the archive format, tgz (the default) or zip:
+
+	curl -o my-svc.tar.gz -d '{"type": "headless-service", "values": {"ProjectName": "my-svc"}}' localhost:8080/generate
+
+Questions not answered take their defaults.  Invalid answers are reported as JSON keyed by prompt, with status 422.  Nothing is written to disk.
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		server := boilerplate.NewServer(serveAddress, boilerplate.NewMetrics("boilerplate"))
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			_ = server.Stop(shutdownCtx)
+		}()
+
+		fmt.Printf("Serving on %s\n", serveAddress)
+		err := server.Start()
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+	},
+}
+
+func init() { //nolint:gochecknoinits // cobra command registration
+	RootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVarP(&serveAddress, "address", "a", ":8080", "Address to listen on")
+}
@@ -0,0 +1,74 @@
+// Copyright © 2023 Nik Ogura <nik.ogura@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nikogura/boilerplate/pkg/boilerplate"
+	"github.com/spf13/cobra"
+	"io/fs"
+	"path/filepath"
+)
+
+var templateDir string  //nolint:gochecknoglobals // cobra command flag
+var templateRepo string //nolint:gochecknoglobals // cobra command flag
+var templateRef string  //nolint:gochecknoglobals // cobra command flag
+
+// addTemplateSourceFlags registers the flags selecting a template tree from outside the binary.
+func addTemplateSourceFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&templateDir, "template-dir", "", "Load templates from a local directory instead of the embedded ones")
+	cmd.Flags().StringVar(&templateRepo, "template-repo", "", "Load templates from a git repository URL instead of the embedded ones")
+	cmd.Flags().StringVar(&templateRef, "template-ref", "", "Branch, tag or commit to check out from --template-repo")
+	cmd.MarkFlagsMutuallyExclusive("template-dir", "template-repo")
+}
+
+// openTemplateSource opens the template tree selected by the template source flags.  It returns a nil fs.FS when
+// the embedded templates should be used.
+func openTemplateSource() (tfs fs.FS, source string, cleanup func(), err error) {
+	cleanup = func() {}
+
+	switch {
+	case templateDir != "":
+		source, err = filepath.Abs(templateDir)
+		if err != nil {
+			return tfs, source, cleanup, err
+		}
+
+		tfs, err = boilerplate.TemplateDirFs(source)
+		return tfs, source, cleanup, err
+
+	case templateRepo != "":
+		source = templateRepo
+		if templateRef != "" {
+			source = fmt.Sprintf("%s@%s", templateRepo, templateRef)
+		}
+
+		var dir string
+		dir, cleanup, err = boilerplate.CloneTemplateRepo(templateRepo, templateRef)
+		if err != nil {
+			return tfs, source, cleanup, err
+		}
+
+		tfs, err = boilerplate.TemplateDirFs(dir)
+		return tfs, source, cleanup, err
+
+	case templateRef != "":
+		err = errors.New("--template-ref requires --template-repo")
+		return tfs, source, cleanup, err
+	}
+
+	return tfs, source, cleanup, err
+}
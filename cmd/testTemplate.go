@@ -0,0 +1,146 @@
+// Copyright © 2023 Nik Ogura <nik.ogura@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nikogura/boilerplate/pkg/boilerplate"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+var goldenDir string        //nolint:gochecknoglobals // cobra command flag
+var updateGolden bool       //nolint:gochecknoglobals // cobra command flag
+var testTemplateType string //nolint:gochecknoglobals // cobra command flag
+
+// testTemplateCmd represents the test-template command.
+var testTemplateCmd = &cobra.Command{ //nolint:gochecknoglobals // cobra command definition
+	Use:   "test-template <template dir>",
+	Short: "Snapshot tests a template tree against a golden copy of its output.",
+	Long: `
+Snapshot tests a template tree against a golden copy of its output.
+
+Renders the template tree in <template dir>, without prompting, and compares the result file by file with a golden tree, printing a unified diff of any differences.  Run it with --update to write the golden tree in the first place, and again whenever the output is meant to change.  Check the golden tree in next to the templates, and run test-template in CI to catch template changes that alter the output by accident.
+
+By default the answers come from testdata/answers.yaml in the template dir, if it exists, and the golden tree lives in testdata/golden.  The testdata directory is never rendered as part of the project.  Answers can be added or overridden with --values and --set, as with 'gen'.  Anything not answered takes its default.
+
+If the tree has no boilerplate.yaml, --type selects one of the built-in sets of questions, as with 'gen --template-dir'.
+
+The now and year template functions always give the start of 2024, so snapshots don't change from year to year.
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, err := filepath.Abs(args[0])
+		if err != nil {
+			log.Fatalf("failed to resolve %s: %v", args[0], err)
+		}
+
+		pt := templateDirType(dir, testTemplateType)
+
+		answersFile := valuesFile
+		if answersFile == "" {
+			answersFile = filepath.Join(dir, "testdata", "answers.yaml")
+			if _, statErr := os.Stat(answersFile); statErr != nil {
+				answersFile = ""
+			}
+		}
+
+		vals := make(map[string]string)
+		if answersFile != "" {
+			vals, err = boilerplate.LoadValuesFile(answersFile)
+			if err != nil {
+				log.Fatalf("failed to load answers: %v", err)
+			}
+		}
+
+		overrides, err := boilerplate.ParseSetValues(setValues)
+		if err != nil {
+			log.Fatalf("failed to parse value overrides: %v", err)
+		}
+
+		rendered, root, err := boilerplate.RenderSnapshot(pt, boilerplate.MergeValues(vals, overrides))
+		if err != nil {
+			log.Fatalf("failed to render %s: %v", dir, err)
+		}
+
+		golden := goldenDir
+		if golden == "" {
+			golden = filepath.Join(dir, "testdata", "golden")
+		}
+
+		if updateGolden {
+			err = boilerplate.UpdateSnapshot(rendered, root, afero.NewOsFs(), golden)
+			if err != nil {
+				log.Fatalf("failed to update golden tree: %v", err)
+			}
+			fmt.Printf("Updated golden tree at %s\n", golden)
+			return
+		}
+
+		err = boilerplate.CompareSnapshot(rendered, root, afero.NewOsFs(), golden)
+		var mismatch *boilerplate.SnapshotMismatchError
+		if errors.As(err, &mismatch) {
+			fmt.Print(mismatch.Diff)
+			fmt.Printf("Output of %s differs from %s.  Rerun with --update if that's intended.\n", dir, golden)
+			os.Exit(1)
+		}
+		if err != nil {
+			log.Fatalf("failed to compare with golden tree: %v", err)
+		}
+
+		fmt.Printf("Output of %s matches %s\n", dir, golden)
+	},
+}
+
+// templateDirType loads the project type for a template tree on disk: the one its boilerplate.yaml declares, or else
+// the built-in type named, with the tree's templates in place of the embedded ones.
+func templateDirType(dir string, typeName string) (pt boilerplate.ProjectType) {
+	templFs, err := boilerplate.TemplateDirFs(dir)
+	if err != nil {
+		log.Fatalf("failed to load templates: %v", err)
+	}
+
+	spec, err := boilerplate.LoadTemplateSpec(templFs, ".")
+	switch {
+	case err == nil:
+		pt = boilerplate.ProjectTypeFromSpec(spec, templFs, ".")
+	case !errors.Is(err, boilerplate.ErrNoTemplateSpec):
+		log.Fatalf("failed to load template spec: %v", err)
+	case typeName == "":
+		log.Fatalf("%s has no %s, so --type is required", dir, boilerplate.TemplateSpecFileName)
+	default:
+		var ok bool
+		pt, ok = boilerplate.LookupProjectType(typeName)
+		if !ok {
+			log.Fatalf("invalid project type: %q. Valid project types are: %s", typeName, boilerplate.ValidProjectTypes())
+		}
+		pt.Fs, pt.Root = templFs, "."
+	}
+
+	return pt
+}
+
+func init() { //nolint:gochecknoinits // cobra command registration
+	RootCmd.AddCommand(testTemplateCmd)
+	testTemplateCmd.Flags().StringVarP(&testTemplateType, "type", "t", "", "Built-in project type whose questions to use, if the tree has no boilerplate.yaml")
+	testTemplateCmd.Flags().StringVarP(&valuesFile, "values", "f", "", "YAML or JSON file of answers keyed by prompt name (default testdata/answers.yaml)")
+	testTemplateCmd.Flags().StringArrayVar(&setValues, "set", nil, "Answer override in the form Key=Value (repeatable)")
+	testTemplateCmd.Flags().StringVar(&goldenDir, "golden", "", "Golden tree to compare with (default testdata/golden in the template dir)")
+	testTemplateCmd.Flags().BoolVar(&updateGolden, "update", false, "Write the golden tree instead of comparing with it")
+}
@@ -15,26 +15,160 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/nikogura/boilerplate/pkg/boilerplate"
 	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
 )
 
-// typesCmd represents the create command.
+var typesOutput string //nolint:gochecknoglobals // cobra command flag
+
+// typesCmd represents the types command.
 var typesCmd = &cobra.Command{ //nolint:gochecknoglobals // cobra command definition
 	Use:   "types",
 	Short: "Lists available boilerplate types.",
 	Long: `
-Lists available boilerplate types.
+Lists available boilerplate types, with a description of each.
+
+Use 'boilerplate types describe <type>' to see the questions a type asks and the files it generates.
 `,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		types := boilerplate.ProjectTypes()
+
+		if typesOutput == "json" {
+			type typeSummary struct {
+				Name        string `json:"name"`
+				Description string `json:"description"`
+			}
+
+			list := make([]typeSummary, 0, len(types))
+			for _, pt := range types {
+				list = append(list, typeSummary{Name: pt.Name, Description: pt.Description})
+			}
+			printJSON(list)
+			return
+		}
+
 		fmt.Printf("Valid Project types:\n")
-		for _, t := range boilerplate.ValidProjectTypes() {
-			fmt.Printf("  %s\n", t)
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, pt := range types {
+			fmt.Fprintf(tw, "  %s\t%s\n", pt.Name, pt.Description)
 		}
+		_ = tw.Flush()
 	},
 }
 
+// typesDescribeCmd represents the types describe command.
+var typesDescribeCmd = &cobra.Command{ //nolint:gochecknoglobals // cobra command definition
+	Use:   "describe <type>",
+	Short: "Describes the questions a boilerplate type asks and the files it generates.",
+	Long: `
+Describes the questions a boilerplate type asks, in the order it asks them, with their defaults and validation rules, and the files it generates.
+
+File paths are resolved with each question's default, or <Key> where there is none.
+
+With --template-dir or --template-repo, describes that template tree instead.  If it has a boilerplate.yaml, the type can be omitted.
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		templFs, _, cleanup, err := openTemplateSource()
+		if err != nil {
+			log.Fatalf("failed to load templates: %v", err)
+		}
+		defer cleanup()
+
+		var pt boilerplate.ProjectType
+		var found bool
+		if templFs != nil {
+			spec, specErr := boilerplate.LoadTemplateSpec(templFs, ".")
+			switch {
+			case specErr == nil:
+				pt, found = boilerplate.ProjectTypeFromSpec(spec, templFs, "."), true
+			case !errors.Is(specErr, boilerplate.ErrNoTemplateSpec):
+				log.Fatalf("failed to load template spec: %v", specErr)
+			}
+		}
+
+		if !found {
+			if len(args) == 0 {
+				log.Fatalf("a project type is required. Valid project types are: %s", boilerplate.ValidProjectTypes())
+			}
+
+			pt, found = boilerplate.LookupProjectType(args[0])
+			if !found {
+				log.Fatalf("invalid project type: %q. Valid project types are: %s", args[0], boilerplate.ValidProjectTypes())
+			}
+
+			if templFs != nil {
+				pt.Fs, pt.Root = templFs, "."
+			}
+		}
+
+		desc, err := pt.Describe()
+		if err != nil {
+			log.Fatalf("failed to describe project type %s: %v", pt.Name, err)
+		}
+
+		if typesOutput == "json" {
+			printJSON(desc)
+			return
+		}
+
+		fmt.Printf("%s - %s\n\nPrompts:\n", desc.Name, desc.Description)
+		for _, p := range desc.Prompts {
+			fmt.Printf("  %s\n    %s\n", p.Key, p.Message)
+			if p.Kind != "" {
+				fmt.Printf("    type:    %s\n", p.Kind)
+			}
+			if len(p.Choices) > 0 {
+				fmt.Printf("    choices: %s\n", strings.Join(p.Choices, ", "))
+			}
+			if p.Default != "" {
+				fmt.Printf("    default: %s\n", p.Default)
+			}
+			if p.When != "" {
+				fmt.Printf("    when:    %s\n", p.When)
+			}
+			if p.Hidden {
+				fmt.Printf("    hidden:  true\n")
+			}
+			if len(p.Validations) > 0 {
+				fmt.Printf("    rules:   %s\n", strings.Join(p.Validations, "\n             "))
+			}
+		}
+
+		fmt.Printf("\nFiles:\n")
+		for _, f := range desc.Files {
+			fmt.Printf("  %s\n", f)
+		}
+
+		if len(desc.Hooks) > 0 {
+			fmt.Printf("\nHooks:\n")
+			for _, h := range desc.Hooks {
+				fmt.Printf("  %s\n", h)
+			}
+		}
+	},
+}
+
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(v)
+	if err != nil {
+		log.Fatalf("failed to encode output: %v", err)
+	}
+}
+
 func init() { //nolint:gochecknoinits // cobra command registration
 	RootCmd.AddCommand(typesCmd)
+	typesCmd.AddCommand(typesDescribeCmd)
+	typesCmd.PersistentFlags().StringVarP(&typesOutput, "output", "o", "text", "Output format: text or json")
+	addTemplateSourceFlags(typesDescribeCmd)
 }
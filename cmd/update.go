@@ -0,0 +1,105 @@
+// Copyright © 2023 Nik Ogura <nik.ogura@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/nikogura/boilerplate/pkg/boilerplate"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+)
+
+var conflictStyle string //nolint:gochecknoglobals // cobra command flag
+
+// updateCmd represents the update command.
+var updateCmd = &cobra.Command{ //nolint:gochecknoglobals // cobra command definition
+	Use:   "update [project dir]",
+	Short: "Updates a generated project to the current templates.",
+	Long: `
+Updates a generated project to the current templates.
+
+Reads the .boilerplate.json manifest written by 'gen', re-renders the project with the templates embedded in this version of boilerplate, and merges the result with any edits made since.
+
+Files you haven't touched are replaced.  Files whose template output hasn't changed keep your edits.  Files changed on both sides are merged against the original rendering, kept in the manifest.  Where your edits and the template changes overlap, the file is a conflict: by default the conflicting regions are wrapped in conflict markers, or with --conflict-style=rej the file is left alone and the template changes are written to a .rej sidecar.
+
+Projects generated from --template-dir or --template-repo are updated from the template tree given by the same flags.
+
+If you omit the project directory, it defaults to the CWD.
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		projDir := ""
+		if len(args) > 0 {
+			projDir = args[0]
+		} else {
+			projDir, err = os.Getwd()
+			if err != nil {
+				log.Fatalf("failed to determine CWD: %v", err)
+			}
+		}
+
+		templFs, _, cleanup, err := openTemplateSource()
+		if err != nil {
+			log.Fatalf("failed to load templates: %v", err)
+		}
+		defer cleanup()
+
+		var result boilerplate.UpdateResult
+		style := boilerplate.ConflictStyle(conflictStyle)
+		if templFs != nil {
+			result, err = boilerplate.UpdateProjectFromFs(afero.NewOsFs(), projDir, templFs, ".", style)
+		} else {
+			result, err = boilerplate.UpdateProject(afero.NewOsFs(), projDir, style)
+		}
+		if err != nil {
+			log.Fatalf("failed to update project: %v", err)
+		}
+
+		printUpdated("Added", result.Added)
+		printUpdated("Updated", result.Updated)
+		printUpdated("Kept local edits", result.Kept)
+		printUpdated("Left deleted", result.Deleted)
+		printUpdated("Merged", result.Merged)
+		printUpdated("Conflicts", result.Conflicts)
+
+		if len(result.Conflicts) > 0 {
+			fmt.Printf("Project updated to boilerplate %s with %d conflict(s) to resolve.\n", boilerplate.VERSION, len(result.Conflicts))
+			cleanup()
+			os.Exit(1)
+		}
+
+		fmt.Printf("Project updated to boilerplate %s\n", boilerplate.VERSION)
+	},
+}
+
+func printUpdated(label string, files []string) {
+	if len(files) == 0 {
+		return
+	}
+
+	fmt.Printf("%s:\n", label)
+	for _, f := range files {
+		fmt.Printf("  %s\n", f)
+	}
+}
+
+func init() { //nolint:gochecknoinits // cobra command registration
+	RootCmd.AddCommand(updateCmd)
+	addTemplateSourceFlags(updateCmd)
+	updateCmd.Flags().StringVar(&conflictStyle, "conflict-style", string(boilerplate.ConflictMarkers), "How to record conflicts: markers or rej")
+}
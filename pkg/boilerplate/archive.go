@@ -0,0 +1,195 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"github.com/spf13/afero"
+	"io"
+	"io/fs"
+	"path/filepath"
+)
+
+// ArchiveFormat is a kind of archive a generated project can be packed into.
+type ArchiveFormat string
+
+const (
+	ArchiveTar ArchiveFormat = "tar"
+	ArchiveTgz ArchiveFormat = "tgz"
+	ArchiveZip ArchiveFormat = "zip"
+)
+
+// ParseArchiveFormat validates an archive format name.  "tar.gz" is taken as tgz.
+func ParseArchiveFormat(s string) (format ArchiveFormat, err error) {
+	switch f := ArchiveFormat(s); f {
+	case ArchiveTar, ArchiveTgz, ArchiveZip:
+		return f, err
+	case "tar.gz":
+		return ArchiveTgz, err
+	}
+
+	return format, fmt.Errorf("invalid archive format %q: must be one of tar, tgz, zip", s)
+}
+
+// ContentType is the MIME type of the format.
+func (f ArchiveFormat) ContentType() string {
+	switch f {
+	case ArchiveTar:
+		return "application/x-tar"
+	case ArchiveZip:
+		return "application/zip"
+	}
+
+	return "application/gzip"
+}
+
+// Ext is the file name extension of the format.
+func (f ArchiveFormat) Ext() string {
+	switch f {
+	case ArchiveTar:
+		return ".tar"
+	case ArchiveZip:
+		return ".zip"
+	}
+
+	return ".tar.gz"
+}
+
+// WriteArchive packs dir in afs, and everything below it, into an archive written to out.  Entries are named relative
+// to dir's parent, so the archive unpacks into a directory of dir's name, and keep their modes.
+func WriteArchive(out io.Writer, afs afero.Fs, dir string, format ArchiveFormat) (err error) {
+	switch format {
+	case ArchiveTar:
+		return writeTar(out, afs, dir)
+	case ArchiveTgz:
+		gz := gzip.NewWriter(out)
+		err = writeTar(gz, afs, dir)
+		if err != nil {
+			return err
+		}
+		return gz.Close()
+	case ArchiveZip:
+		return writeZip(out, afs, dir)
+	}
+
+	return fmt.Errorf("invalid archive format %q", format)
+}
+
+// walkArchive calls fn for dir and everything below it, in lexical order, with the slash separated name it gets in
+// an archive.
+func walkArchive(afs afero.Fs, dir string, fn func(name string, path string, info fs.FileInfo) error) error {
+	base := filepath.Dir(filepath.Clean(dir))
+	return afero.Walk(afs, dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+
+		return fn(filepath.ToSlash(rel), path, info)
+	})
+}
+
+func writeTar(out io.Writer, afs afero.Fs, dir string) (err error) {
+	tw := tar.NewWriter(out)
+	err = walkArchive(afs, dir, func(name string, path string, info fs.FileInfo) error {
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %w", path, err)
+		}
+		hdr.Name = name
+		hdr.Mode = int64(archiveMode(info).Perm())
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %w", path, err)
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		return copyFileTo(tw, afs, path)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+func writeZip(out io.Writer, afs afero.Fs, dir string) (err error) {
+	zw := zip.NewWriter(out)
+	err = walkArchive(afs, dir, func(name string, path string, info fs.FileInfo) error {
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %w", path, err)
+		}
+		hdr.Name = name
+		hdr.SetMode(archiveMode(info))
+		if info.IsDir() {
+			hdr.Name += "/"
+		} else {
+			hdr.Method = zip.Deflate
+		}
+
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %w", path, err)
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		return copyFileTo(w, afs, path)
+	})
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// archiveMode is the mode info gets in an archive.  In-memory file systems can create directories without any
+// permissions, which would make them unusable once unpacked.
+func archiveMode(info fs.FileInfo) fs.FileMode {
+	mode := info.Mode()
+	if mode.IsDir() && mode.Perm() == 0 {
+		mode |= 0755
+	}
+
+	return mode
+}
+
+// copyFileTo copies the contents of path in afs to w.
+func copyFileTo(w io.Writer, afs afero.Fs, path string) (err error) {
+	f, err := afs.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", path, err)
+	}
+
+	return err
+}
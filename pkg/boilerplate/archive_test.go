@@ -0,0 +1,103 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteArchive(t *testing.T) {
+	afs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(afs, "/out/demo/main.go", []byte("package main\n"), 0644))
+	require.NoError(t, afero.WriteFile(afs, "/out/demo/scripts/build.sh", []byte("#!/bin/sh\n"), 0755))
+	require.NoError(t, afero.WriteFile(afs, "/out/other.txt", []byte("not in the project\n"), 0644))
+
+	want := map[string]int64{
+		"demo/":                 0755,
+		"demo/main.go":          0644,
+		"demo/scripts/":         0755,
+		"demo/scripts/build.sh": 0755,
+	}
+
+	for _, format := range []ArchiveFormat{ArchiveTar, ArchiveTgz} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteArchive(&buf, afs, "/out/demo", format))
+
+			var r io.Reader = &buf
+			if format == ArchiveTgz {
+				gz, err := gzip.NewReader(r)
+				require.NoError(t, err)
+				r = gz
+			}
+
+			got := make(map[string]int64)
+			tr := tar.NewReader(r)
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				got[hdr.Name] = hdr.Mode & 0777
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+
+	t.Run("zip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteArchive(&buf, afs, "/out/demo", ArchiveZip))
+
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+
+		got := make(map[string]int64)
+		for _, f := range zr.File {
+			got[f.Name] = int64(f.Mode().Perm())
+		}
+		assert.Equal(t, want, got)
+	})
+
+	_, err := ParseArchiveFormat("rar")
+	assert.Error(t, err)
+	format, err := ParseArchiveFormat("tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, ArchiveTgz, format)
+}
+
+// TestWriteArchive_Generated checks a project generated for an archive, hooks and all, packs without a repository.
+func TestWriteArchive_Generated(t *testing.T) {
+	afs := afero.NewMemMapFs()
+	res, err := Generate(context.Background(), Options{Type: CobraProjectType, Values: goldenAnswers(), OutFs: afs, Dest: "/", Hooks: true, Archive: true})
+	require.NoError(t, err)
+	for _, h := range res.Hooks {
+		assert.NotContains(t, []string{HookGitInit, HookPreCommit}, h.Name)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteArchive(&buf, afs, res.ProjectDir, ArchiveZip))
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.NotEmpty(t, zr.File)
+	for _, f := range zr.File {
+		assert.NotContains(t, f.Name, ".git/")
+	}
+}
@@ -0,0 +1,102 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"embed"
+	"golang.org/x/mod/module"
+	"log"
+)
+
+// Names of the built-in features.
+const (
+	FeatureMetrics    = "metrics"
+	FeaturePostgres   = "postgres"
+	FeatureOIDCAuth   = "oidc-auth"
+	FeatureGRPCServer = "grpc-server"
+	FeatureDockerfile = "dockerfile"
+	FeatureGitHubCI   = "github-ci"
+	FeatureHelm       = "helm"
+)
+
+// featureTemplates holds the files each built-in feature adds, at feature_templates/_<name>/{{.ProjectName}}/...
+//
+//go:embed all:feature_templates
+var featureTemplates embed.FS
+
+// rootCmdFile is where the Go project types define rootCmd, and so where subcommands are added.
+const rootCmdFile = "cmd/root.go"
+
+func init() { //nolint:gochecknoinits // built-in feature registration
+	tidy := []Hook{GoModTidyHook()}
+
+	for _, f := range []Feature{
+		{
+			Name:        FeatureMetrics,
+			Description: "A metrics subcommand serving Prometheus metrics, with a registry to add your own to.",
+			Types:       []string{CobraProjectType},
+			Patches:     []GoPatch{AddSubcommandPatch(rootCmdFile, "rootCmd", "metricsCmd")},
+			Requires:    []module.Version{{Path: "github.com/prometheus/client_golang", Version: "v1.20.5"}},
+			Hooks:       tidy,
+		},
+		{
+			Name:        FeaturePostgres,
+			Description: "A Postgres connection package using pgx, and a db subcommand to check the connection.",
+			Types:       []string{CobraProjectType, HeadlessServiceType, IndirectSelectionType},
+			Patches:     []GoPatch{AddSubcommandPatch(rootCmdFile, "rootCmd", "dbCmd")},
+			Requires:    []module.Version{{Path: "github.com/jackc/pgx/v5", Version: "v5.7.1"}},
+			Hooks:       tidy,
+		},
+		{
+			Name:        FeatureOIDCAuth,
+			Description: "An OIDC bearer token guard for the service's routes, and a /whoami route using it.",
+			Types:       []string{HeadlessServiceType},
+			Patches: []GoPatch{AddRoutePatch("pkg/{{.ProjectPackageName}}/server.go", "NewServer", "mux", "/whoami",
+				`s.metricsMiddleware("/whoami", "GET", s.requireOIDC(s.whoamiHandler))`)},
+			Requires: []module.Version{{Path: "github.com/coreos/go-oidc/v3", Version: "v3.11.0"}},
+			Hooks:    tidy,
+		},
+		{
+			Name:        FeatureGRPCServer,
+			Description: "A gRPC server with health checks and reflection, run by a grpc subcommand.",
+			Types:       []string{CobraProjectType, HeadlessServiceType},
+			Patches:     []GoPatch{AddSubcommandPatch(rootCmdFile, "rootCmd", "grpcCmd")},
+			Requires: []module.Version{
+				{Path: "google.golang.org/grpc", Version: "v1.67.1"},
+				// Older dependencies, e.g. of viper, pull in genproto from before it was split into modules, which
+				// makes grpc's imports of those modules ambiguous.
+				{Path: "google.golang.org/genproto", Version: "v0.0.0-20240814211410-ddb44dafa142"},
+			},
+			Hooks: tidy,
+		},
+		{
+			Name:        FeatureDockerfile,
+			Description: "A multi-stage Dockerfile building a distroless image.",
+			Types:       []string{CobraProjectType, IndirectSelectionType},
+		},
+		{
+			Name:        FeatureGitHubCI,
+			Description: "A GitHub Actions workflow that lints, tests and builds the project.",
+			Types:       []string{CobraProjectType},
+		},
+		{
+			Name:        FeatureHelm,
+			Description: "A Helm chart deploying the service.",
+			Types:       []string{HeadlessServiceType, IndirectSelectionType},
+		},
+	} {
+		f.Fs = featureTemplates
+		f.Root = "feature_templates/_" + f.Name
+		err := RegisterFeature(f)
+		if err != nil {
+			log.Fatalf("failed to register built-in feature: %v", err)
+		}
+	}
+}
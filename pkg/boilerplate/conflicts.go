@@ -0,0 +1,129 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictPolicy controls what BuildProject does with files that already exist in the destination.
+type ConflictPolicy string
+
+const (
+	// OnConflictFail refuses to write anything if any file already exists.  This is the default.
+	OnConflictFail ConflictPolicy = "fail"
+	// OnConflictSkip leaves existing files alone and writes everything else.
+	OnConflictSkip ConflictPolicy = "skip"
+	// OnConflictOverwrite replaces existing files.
+	OnConflictOverwrite ConflictPolicy = "overwrite"
+	// OnConflictBackup renames existing files to a .bak sidecar before writing.
+	OnConflictBackup ConflictPolicy = "backup"
+)
+
+// ParseConflictPolicy validates a conflict policy name.
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch p := ConflictPolicy(s); p {
+	case OnConflictFail, OnConflictSkip, OnConflictOverwrite, OnConflictBackup:
+		return p, nil
+	}
+
+	return "", fmt.Errorf("invalid conflict policy %q: must be one of fail, skip, overwrite, backup", s)
+}
+
+// CollisionError lists every file that already exists in the destination.
+type CollisionError struct {
+	Paths []string
+}
+
+func (e *CollisionError) Error() string {
+	return fmt.Sprintf("%d file(s) already exist in the destination:\n  %s", len(e.Paths), strings.Join(e.Paths, "\n  "))
+}
+
+// Collisions lists the files, relative to destDir, that BuildProject would write over.  Paths must already be resolved.
+func (w TmplWriter) Collisions(destDir string) (collisions []string, err error) {
+	targets := []string{filepath.Join(w.ProjectRoot(), ManifestFileName)}
+	for _, fp := range w.FilePaths {
+		if !fp.IsDir && !fp.Excluded {
+			targets = append(targets, fp.TemplPath)
+		}
+	}
+
+	for _, target := range targets {
+		info, statErr := w.OutFs.Stat(filepath.Join(destDir, target))
+		if statErr != nil {
+			if os.IsNotExist(statErr) {
+				continue
+			}
+			return collisions, fmt.Errorf("failed to stat %s: %w", target, statErr)
+		}
+
+		if !info.IsDir() {
+			collisions = append(collisions, target)
+		}
+	}
+
+	return collisions, err
+}
+
+// resolveConflicts applies the conflict policy to colliding files before anything is written, returning the paths to
+// leave alone and the paths to back up.
+func (w TmplWriter) resolveConflicts(destDir string) (skip map[string]bool, backups []string, err error) {
+	collisions, err := w.Collisions(destDir)
+	if err != nil || len(collisions) == 0 {
+		return skip, backups, err
+	}
+
+	switch w.OnConflict {
+	case OnConflictOverwrite:
+		return skip, backups, err
+
+	case OnConflictSkip:
+		skip = make(map[string]bool, len(collisions))
+		for _, c := range collisions {
+			skip[c] = true
+		}
+		return skip, backups, err
+
+	case OnConflictBackup:
+		return skip, collisions, err
+
+	case "", OnConflictFail:
+		return skip, backups, &CollisionError{Paths: collisions}
+	}
+
+	return skip, backups, fmt.Errorf("invalid conflict policy %q", w.OnConflict)
+}
+
+// backupFile moves path aside to the first free .bak name, returning that name.
+func (w TmplWriter) backupFile(path string) (backup string, err error) {
+	backup = path + ".bak"
+	for i := 1; ; i++ {
+		_, statErr := w.OutFs.Stat(backup)
+		if os.IsNotExist(statErr) {
+			break
+		}
+		if statErr != nil {
+			err = fmt.Errorf("failed to stat %s: %w", backup, statErr)
+			return backup, err
+		}
+		backup = fmt.Sprintf("%s.bak.%d", path, i)
+	}
+
+	err = w.OutFs.Rename(path, backup)
+	if err != nil {
+		err = fmt.Errorf("failed to back up %s: %w", path, err)
+		return backup, err
+	}
+
+	return backup, err
+}
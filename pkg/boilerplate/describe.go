@@ -0,0 +1,132 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"fmt"
+	"github.com/spf13/afero"
+	"sort"
+	"strings"
+)
+
+// TypeDescription describes what a project type asks for and what it generates.
+type TypeDescription struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Prompts     []PromptDescription `json:"prompts"`
+	Files       []string            `json:"files"`
+	Hooks       []string            `json:"hooks,omitempty"`
+}
+
+// PromptDescription describes a single question a project type asks.
+type PromptDescription struct {
+	Key         string   `json:"key"`
+	Message     string   `json:"message"`
+	Kind        string   `json:"kind,omitempty"`
+	Choices     []string `json:"choices,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	When        string   `json:"when,omitempty"`
+	Hidden      bool     `json:"hidden,omitempty"`
+	Validations []string `json:"validations,omitempty"`
+}
+
+// Describe lists the questions the project type asks, in the order it asks them, the files it generates, and the
+// hooks run afterwards.  File paths are resolved with each question's default, or <Key> where there is none.
+func (pt ProjectType) Describe() (desc TypeDescription, err error) {
+	desc = TypeDescription{
+		Name:        pt.Name,
+		Description: pt.Description,
+	}
+
+	for _, h := range pt.Hooks {
+		desc.Hooks = append(desc.Hooks, h.Name)
+	}
+
+	vals, prompts, err := pt.sampleValues(func(key ParamPrompt) string { return fmt.Sprintf("<%s>", key) }, nil)
+	if err != nil {
+		return desc, err
+	}
+	desc.Prompts = prompts
+
+	w, err := NewTmplWriterFromFs(afero.NewMemMapFs(), pt.Fs, pt.Root, pt.Name, vals)
+	if err != nil {
+		return desc, err
+	}
+
+	err = w.ResolveAllPathTemplates()
+	if err != nil {
+		return desc, err
+	}
+
+	for _, fp := range w.FilePaths {
+		if !fp.IsDir && !fp.Excluded {
+			desc.Files = append(desc.Files, fp.TemplPath)
+		}
+	}
+	sort.Strings(desc.Files)
+
+	return desc, err
+}
+
+// sampleValues answers every question the project type asks with the answer given, else its default, else
+// placeholder(key).  It returns the resulting template values, and the questions in the order they are asked.
+func (pt ProjectType) sampleValues(placeholder func(key ParamPrompt) string, answers map[ParamPrompt]string) (vals map[string]any, asked []PromptDescription, err error) {
+	params := pt.NewParams()
+	prompts := pt.Prompts()
+	values := params.Values()
+
+	for _, key := range promptOrderFor(params, prompts) {
+		p := prompts[key]
+		if values[key] == nil {
+			continue
+		}
+
+		def := promptDefault(p, values)
+		pd := PromptDescription{
+			Key:     string(key),
+			Message: p.PromptMsg,
+			Kind:    string(p.Kind),
+			Choices: p.Choices,
+			Default: def,
+			When:    p.When,
+			Hidden:  p.Hidden,
+		}
+
+		for _, v := range p.Validations {
+			pd.Validations = append(pd.Validations, strings.TrimPrefix(v.InvalidMsg, "Error: "))
+		}
+
+		asked = append(asked, pd)
+
+		answer, given := answers[key]
+		switch {
+		case !promptApplies(p, values):
+		case given:
+			*values[key] = answer
+		case def != "":
+			*values[key] = def
+		default:
+			*values[key] = placeholder(key)
+		}
+	}
+
+	// Every question is answered now, so this only applies the type's post-processing.
+	err = pt.FromPrompts(params, strings.NewReader(""))
+	if err != nil {
+		return vals, asked, fmt.Errorf("failed to apply defaults for %s: %w", pt.Name, err)
+	}
+
+	vals, err = params.AsMap()
+	if err != nil {
+		return vals, asked, fmt.Errorf("failed to export params for %s: %w", pt.Name, err)
+	}
+
+	return vals, asked, err
+}
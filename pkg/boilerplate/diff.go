@@ -0,0 +1,396 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	diffEqual  = ' '
+	diffDelete = '-'
+	diffInsert = '+'
+)
+
+type diffOp struct {
+	Kind byte
+	Line string
+}
+
+// splitLines splits text into lines, keeping the line terminators.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines computes a minimal line edit script turning a into b using the Myers algorithm, in its linear space
+// form: the middle snake of each shortest path is found, and the halves either side of it are diffed in turn.
+func diffLines(a, b []string) []diffOp {
+	return appendDiff(make([]diffOp, 0, max(len(a), len(b))), a, b)
+}
+
+// appendDiff appends the edit script turning a into b to ops.
+func appendDiff(ops []diffOp, a, b []string) []diffOp {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		ops = append(ops, diffOp{Kind: diffEqual, Line: a[0]})
+		a, b = a[1:], b[1:]
+	}
+
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			ops = append(ops, diffOp{Kind: diffInsert, Line: line})
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			ops = append(ops, diffOp{Kind: diffDelete, Line: line})
+		}
+	default:
+		x, y := middleSnake(a, b)
+		ops = appendDiff(ops, a[:x], b[:y])
+		ops = appendDiff(ops, a[x:], b[y:])
+	}
+
+	for _, line := range common {
+		ops = append(ops, diffOp{Kind: diffEqual, Line: line})
+	}
+
+	return ops
+}
+
+// middleSnake searches for a shortest path through the edit graph of a and b from both ends at once, keeping only
+// the furthest point reached on each diagonal, and returns where the two searches meet.  a and b must be non-empty,
+// and differ in their first and last lines, so the point is never either corner.
+func middleSnake(a, b []string) (x, y int) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	delta := n - m
+	odd := delta%2 != 0
+
+	// vf holds the furthest x reached forwards on each diagonal, vb the furthest reached backwards, counted from the
+	// end.  -1 marks a diagonal not reached yet.
+	vf := make([]int, 2*maxD+2)
+	vb := make([]int, 2*maxD+2)
+	for i := range vf {
+		vf[i], vb[i] = -1, -1
+	}
+	vf[offset+1], vb[offset+1] = 0, 0
+
+	// Diagonals that have run off the edge of the graph are trimmed from either end of the search.
+	var fStart, fEnd, bStart, bEnd int
+	for d := 0; d < maxD; d++ {
+		for k := -d + fStart; k <= d-fEnd; k += 2 {
+			i := offset + k
+			var fx int
+			if k == -d || (k != d && vf[i-1] < vf[i+1]) {
+				fx = vf[i+1]
+			} else {
+				fx = vf[i-1] + 1
+			}
+
+			fy := fx - k
+			for fx < n && fy < m && a[fx] == b[fy] {
+				fx++
+				fy++
+			}
+			vf[i] = fx
+
+			switch {
+			case fx > n:
+				fEnd += 2
+			case fy > m:
+				fStart += 2
+			case odd:
+				j := offset + delta - k
+				if j >= 0 && j < len(vb) && vb[j] != -1 && fx >= n-vb[j] {
+					return fx, fy
+				}
+			}
+		}
+
+		for k := -d + bStart; k <= d-bEnd; k += 2 {
+			i := offset + k
+			var bx int
+			if k == -d || (k != d && vb[i-1] < vb[i+1]) {
+				bx = vb[i+1]
+			} else {
+				bx = vb[i-1] + 1
+			}
+
+			by := bx - k
+			for bx < n && by < m && a[n-bx-1] == b[m-by-1] {
+				bx++
+				by++
+			}
+			vb[i] = bx
+
+			switch {
+			case bx > n:
+				bEnd += 2
+			case by > m:
+				bStart += 2
+			case !odd:
+				j := offset + delta - k
+				if j >= 0 && j < len(vf) && vf[j] != -1 && vf[j] >= n-bx {
+					return vf[j], vf[j] - (j - offset)
+				}
+			}
+		}
+	}
+
+	// The searches always meet by maxD, but should they not, deleting all of a and inserting all of b still holds.
+	return n, 0
+}
+
+// UnifiedDiff renders a unified diff between two texts.  It returns an empty string when they are identical.
+func UnifiedDiff(fromName, toName, from, to string) string {
+	const context = 3
+
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	var changes []int
+	for i, op := range ops {
+		if op.Kind != diffEqual {
+			changes = append(changes, i)
+		}
+	}
+
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	// Line numbers in a and b at the start of every op.
+	aLine := make([]int, len(ops)+1)
+	bLine := make([]int, len(ops)+1)
+	for i, op := range ops {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if op.Kind != diffInsert {
+			aLine[i+1]++
+		}
+		if op.Kind != diffDelete {
+			bLine[i+1]++
+		}
+	}
+
+	for c := 0; c < len(changes); {
+		start := max(changes[c]-context, 0)
+		end := changes[c]
+		for c < len(changes) && changes[c] <= end+2*context {
+			end = changes[c]
+			c++
+		}
+		end = min(end+context, len(ops)-1)
+
+		aCount := aLine[end+1] - aLine[start]
+		bCount := bLine[end+1] - bLine[start]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aLine[start], aCount), hunkRange(bLine[start], bCount))
+
+		for _, op := range ops[start : end+1] {
+			sb.WriteByte(op.Kind)
+			sb.WriteString(op.Line)
+			if !strings.HasSuffix(op.Line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// mergeWithMarkers merges theirs into ours, wrapping every region where the two differ in conflict markers.
+func mergeWithMarkers(ours, theirs, theirsLabel string) string {
+	ops := diffLines(splitLines(ours), splitLines(theirs))
+
+	var sb strings.Builder
+	var del, ins []string
+
+	flush := func() {
+		if len(del) == 0 && len(ins) == 0 {
+			return
+		}
+
+		sb.WriteString("<<<<<<< yours\n")
+		writeMarkerLines(&sb, del)
+		sb.WriteString("=======\n")
+		writeMarkerLines(&sb, ins)
+		fmt.Fprintf(&sb, ">>>>>>> %s\n", theirsLabel)
+		del, ins = nil, nil
+	}
+
+	for _, op := range ops {
+		switch op.Kind {
+		case diffDelete:
+			del = append(del, op.Line)
+		case diffInsert:
+			ins = append(ins, op.Line)
+		default:
+			flush()
+			sb.WriteString(op.Line)
+		}
+	}
+	flush()
+
+	return sb.String()
+}
+
+func writeMarkerLines(sb *strings.Builder, lines []string) {
+	for _, l := range lines {
+		sb.WriteString(l)
+		if !strings.HasSuffix(l, "\n") {
+			sb.WriteByte('\n')
+		}
+	}
+}
+
+// diffHunk is a change replacing lines [Start, End) of the base with Lines.
+type diffHunk struct {
+	Start int
+	End   int
+	Lines []string
+}
+
+// diffHunks groups the edit script turning base into other into hunks, each a run of changed lines.
+func diffHunks(base, other []string) []diffHunk {
+	var hunks []diffHunk
+	var cur *diffHunk
+	i := 0
+	for _, op := range diffLines(base, other) {
+		if op.Kind == diffEqual {
+			if cur != nil {
+				hunks = append(hunks, *cur)
+				cur = nil
+			}
+			i++
+			continue
+		}
+
+		if cur == nil {
+			cur = &diffHunk{Start: i, End: i}
+		}
+		if op.Kind == diffDelete {
+			i++
+			cur.End = i
+		} else {
+			cur.Lines = append(cur.Lines, op.Line)
+		}
+	}
+	if cur != nil {
+		hunks = append(hunks, *cur)
+	}
+
+	return hunks
+}
+
+// applyHunks returns base[start:end] with hunks, which must lie within it, applied.
+func applyHunks(base []string, start, end int, hunks []diffHunk) []string {
+	var out []string
+	pos := start
+	for _, h := range hunks {
+		out = append(out, base[pos:h.Start]...)
+		out = append(out, h.Lines...)
+		pos = h.End
+	}
+
+	return append(out, base[pos:end]...)
+}
+
+// merge3 merges the changes made to base in ours and in theirs.  Where only one side changed a region, or both
+// changed it the same way, that change is taken.  Regions both sides changed differently, including changes that
+// touch, are conflicts, and are wrapped in markers as mergeWithMarkers does.  clean reports there were none.
+func merge3(base, ours, theirs, theirsLabel string) (merged string, clean bool) {
+	baseLines := splitLines(base)
+	oursHunks := diffHunks(baseLines, splitLines(ours))
+	theirsHunks := diffHunks(baseLines, splitLines(theirs))
+
+	var sb strings.Builder
+	clean = true
+	pos := 0
+	for len(oursHunks) > 0 || len(theirsHunks) > 0 {
+		// Start from the earliest hunk on either side, and take in every hunk overlapping or touching the region.
+		first := theirsHunks
+		if len(theirsHunks) == 0 || (len(oursHunks) > 0 && oursHunks[0].Start < theirsHunks[0].Start) {
+			first = oursHunks
+		}
+		start, end := first[0].Start, first[0].End
+
+		var ourGroup, theirGroup []diffHunk
+		for grew := true; grew; {
+			grew = false
+			for len(oursHunks) > 0 && oursHunks[0].Start <= end {
+				end = max(end, oursHunks[0].End)
+				ourGroup = append(ourGroup, oursHunks[0])
+				oursHunks = oursHunks[1:]
+				grew = true
+			}
+			for len(theirsHunks) > 0 && theirsHunks[0].Start <= end {
+				end = max(end, theirsHunks[0].End)
+				theirGroup = append(theirGroup, theirsHunks[0])
+				theirsHunks = theirsHunks[1:]
+				grew = true
+			}
+		}
+
+		sb.WriteString(strings.Join(baseLines[pos:start], ""))
+		pos = end
+
+		ourLines := applyHunks(baseLines, start, end, ourGroup)
+		theirLines := applyHunks(baseLines, start, end, theirGroup)
+		switch {
+		case len(theirGroup) == 0 || slices.Equal(ourLines, theirLines):
+			sb.WriteString(strings.Join(ourLines, ""))
+		case len(ourGroup) == 0:
+			sb.WriteString(strings.Join(theirLines, ""))
+		default:
+			clean = false
+			sb.WriteString("<<<<<<< yours\n")
+			writeMarkerLines(&sb, ourLines)
+			sb.WriteString("=======\n")
+			writeMarkerLines(&sb, theirLines)
+			fmt.Fprintf(&sb, ">>>>>>> %s\n", theirsLabel)
+		}
+	}
+
+	sb.WriteString(strings.Join(baseLines[pos:], ""))
+
+	return sb.String(), clean
+}
@@ -0,0 +1,214 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	for _, tc := range []struct {
+		Name string
+		From string
+		To   string
+		Want string
+	}{
+		{
+			Name: "Identical",
+			From: "a\nb\n",
+			To:   "a\nb\n",
+			Want: "",
+		},
+		{
+			Name: "Changed line",
+			From: "a\nb\nc\n",
+			To:   "a\nB\nc\n",
+			Want: "--- from\n+++ to\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			Name: "New file",
+			From: "",
+			To:   "a\n",
+			Want: "--- from\n+++ to\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			Name: "Missing trailing newline",
+			From: "a\n",
+			To:   "a\nb",
+			Want: "--- from\n+++ to\n@@ -1 +1,2 @@\n a\n+b\n\\ No newline at end of file\n",
+		},
+		{
+			Name: "Separate hunks",
+			From: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			To:   "x\n2\n3\n4\n5\n6\n7\n8\n9\ny\n",
+			Want: "--- from\n+++ to\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+y\n",
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Want, UnifiedDiff("from", "to", tc.From, tc.To))
+		})
+	}
+}
+
+func TestMergeWithMarkers(t *testing.T) {
+	merged := mergeWithMarkers("a\nmine\nc\n", "a\ntheirs\nc\n", "boilerplate")
+	assert.Equal(t, "a\n<<<<<<< yours\nmine\n=======\ntheirs\n>>>>>>> boilerplate\nc\n", merged)
+}
+
+func TestMerge3(t *testing.T) {
+	for _, tc := range []struct {
+		Name   string
+		Base   string
+		Ours   string
+		Theirs string
+		Want   string
+		Clean  bool
+	}{
+		{
+			Name:   "Separate edits",
+			Base:   "a\nb\nc\nd\ne\n",
+			Ours:   "A\nb\nc\nd\ne\n",
+			Theirs: "a\nb\nc\nd\nE\nf\n",
+			Want:   "A\nb\nc\nd\nE\nf\n",
+			Clean:  true,
+		},
+		{
+			Name:   "Same edit on both sides",
+			Base:   "a\nb\nc\n",
+			Ours:   "a\nB\nc\n",
+			Theirs: "a\nB\nc\n",
+			Want:   "a\nB\nc\n",
+			Clean:  true,
+		},
+		{
+			Name:   "Appended and prepended",
+			Base:   "a\nb\n",
+			Ours:   "a\nb\nmine\n",
+			Theirs: "theirs\na\nb\n",
+			Want:   "theirs\na\nb\nmine\n",
+			Clean:  true,
+		},
+		{
+			Name:   "Overlapping edits",
+			Base:   "a\nb\nc\n",
+			Ours:   "a\nmine\nc\n",
+			Theirs: "a\ntheirs\nc\n",
+			Want:   "a\n<<<<<<< yours\nmine\n=======\ntheirs\n>>>>>>> boilerplate\nc\n",
+		},
+		{
+			Name:   "Touching edits",
+			Base:   "a\nb\nc\n",
+			Ours:   "A\nb\nc\n",
+			Theirs: "a\nB\nc\n",
+			Want:   "<<<<<<< yours\nA\nb\n=======\na\nB\n>>>>>>> boilerplate\nc\n",
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			merged, clean := merge3(tc.Base, tc.Ours, tc.Theirs, "boilerplate")
+			assert.Equal(t, tc.Want, merged)
+			assert.Equal(t, tc.Clean, clean)
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	// Against the length of the longest common subsequence, on small random inputs with plenty of repeats.
+	rng := rand.New(rand.NewPCG(1, 2))
+	random := func() []string {
+		lines := make([]string, rng.IntN(12))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.IntN(3)))
+		}
+		return lines
+	}
+
+	for range 500 {
+		a, b := random(), random()
+		ops := diffLines(a, b)
+		from, to := applyOps(ops)
+		require.Equal(t, a, from)
+		require.Equal(t, b, to)
+		require.Equal(t, len(a)+len(b)-2*lcsLen(a, b), len(ops)-countOps(ops, diffEqual), "%q -> %q", a, b)
+	}
+}
+
+// TestDiffLines_Large diffs two long files with nothing in common, which a diff keeping every step of its search
+// would need gigabytes for.
+func TestDiffLines_Large(t *testing.T) {
+	const n = 5000
+	a := make([]string, n)
+	b := make([]string, n)
+	for i := range n {
+		a[i] = fmt.Sprintf("a%d\n", i)
+		b[i] = fmt.Sprintf("b%d\n", i)
+	}
+	// A few lines in common, scattered through both.
+	for i := 0; i < n; i += 97 {
+		b[(i*7)%n] = a[i]
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	ops := diffLines(a, b)
+	runtime.ReadMemStats(&after)
+
+	from, to := applyOps(ops)
+	assert.Equal(t, a, from)
+	assert.Equal(t, b, to)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(64<<20))
+}
+
+// applyOps rebuilds both sides of an edit script.
+func applyOps(ops []diffOp) (from, to []string) {
+	from, to = []string{}, []string{}
+	for _, op := range ops {
+		if op.Kind != diffInsert {
+			from = append(from, op.Line)
+		}
+		if op.Kind != diffDelete {
+			to = append(to, op.Line)
+		}
+	}
+
+	return from, to
+}
+
+func countOps(ops []diffOp, kind byte) (count int) {
+	for _, op := range ops {
+		if op.Kind == kind {
+			count++
+		}
+	}
+
+	return count
+}
+
+func lcsLen(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,478 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidFeature is returned for a feature that isn't registered.
+var ErrInvalidFeature = errors.New("invalid feature")
+
+// Feature is something added to a project after it has been generated, e.g. a metrics endpoint or a Helm chart.  It
+// adds new files, rendered with the values the project was generated from, and patches existing Go files at known
+// anchor points, e.g. registering a subcommand with rootCmd.
+type Feature struct {
+	Name        string
+	Description string
+
+	// Types lists the project types the feature suits.  Empty means any.  A project without a manifest has no known
+	// type, so is let through, and fails if an anchor the feature patches is missing.
+	Types []string
+
+	// Fs holds the files to add at Root, laid out like a project type's templates, i.e. Root/{{.ProjectName}}/...
+	Fs   fs.FS
+	Root string
+
+	// Patches edit the project's existing Go files, in order.
+	Patches []GoPatch
+
+	// Requires are modules the added code needs, added to go.mod unless it already requires them.
+	Requires []module.Version
+
+	// Hooks run in order once the feature has been added.
+	Hooks []Hook
+}
+
+// AddOptions say which feature AddFeature adds, and to which project.
+type AddOptions struct {
+	// Feature names a registered feature.
+	Feature string
+
+	// OutFs holds the project.  The default is the OS file system.
+	OutFs afero.Fs
+
+	// ProjectDir is the root of the project.  The default is the current directory.
+	ProjectDir string
+
+	// DryRun works out what would change without writing anything.
+	DryRun bool
+
+	// Hooks runs the feature's hooks once it has been added.
+	Hooks bool
+}
+
+// AddResult reports what AddFeature did, or with DryRun, would do.
+type AddResult struct {
+	Feature string
+
+	// ProjectType is the type recorded in the project's manifest, if it has one.
+	ProjectType string
+
+	// Added lists the new files, and Patched the existing files changed, relative to the project root.
+	Added   []string
+	Patched []string
+
+	// Required lists the modules added to go.mod, as path@version.
+	Required []string
+
+	// Hooks reports on each hook run.
+	Hooks []HookResult
+}
+
+// AddFeature adds a feature to an existing project.  The project's answers come from its manifest, or, failing that,
+// its go.mod (see ProjectValues).  Every change is worked out before anything is written, so a feature that can't be
+// added, e.g. because one of its files already exists or an anchor is missing, leaves the project as it was.  So does
+// one that fails to write, since its changes are staged and rolled back as gen's are.  Everything written is recorded
+// in the manifest, if there is one, as boilerplate's rather than the user's.
+func AddFeature(ctx context.Context, opts AddOptions) (res AddResult, err error) {
+	f, ok := LookupFeature(opts.Feature)
+	if !ok {
+		err = invalidFeatureError(opts.Feature)
+		return res, err
+	}
+	res.Feature = f.Name
+
+	outFs := opts.OutFs
+	if outFs == nil {
+		outFs = afero.NewOsFs()
+	}
+
+	projDir := opts.ProjectDir
+	if projDir == "" {
+		projDir = "."
+	}
+
+	// Changes are staged at the same path, so it mustn't depend on the working directory.
+	projDir, err = filepath.Abs(projDir)
+	if err != nil {
+		err = fmt.Errorf("failed to resolve project directory: %w", err)
+		return res, err
+	}
+
+	m, hasManifest, err := loadManifestIfAny(outFs, projDir)
+	if err != nil {
+		return res, err
+	}
+	res.ProjectType = m.ProjectType
+
+	if hasManifest && len(f.Types) > 0 && !slices.Contains(f.Types, m.ProjectType) {
+		err = fmt.Errorf("feature %s is for %s projects, not %s", f.Name, strings.Join(f.Types, ", "), m.ProjectType)
+		return res, err
+	}
+
+	if slices.Contains(m.Features, f.Name) {
+		err = fmt.Errorf("feature %s has already been added to %s", f.Name, projDir)
+		return res, err
+	}
+
+	vals, err := projectValues(outFs, projDir, m)
+	if err != nil {
+		return res, err
+	}
+
+	changes, err := f.plan(outFs, projDir, vals, &res)
+	if err != nil {
+		return res, err
+	}
+
+	if opts.DryRun {
+		return res, err
+	}
+
+	err = ctx.Err()
+	if err != nil {
+		return res, err
+	}
+
+	// Stage everything, manifest included, and copy it in as gen does, so a failure part way leaves nothing behind.
+	staged := afero.NewMemMapFs()
+	for _, rel := range sortedKeys(changes) {
+		path := filepath.Join(projDir, rel)
+		err = writeFileAll(staged, path, changes[rel])
+		if err != nil {
+			return res, err
+		}
+
+		// Patched files keep their mode.
+		info, statErr := outFs.Stat(path)
+		if statErr == nil {
+			err = staged.Chmod(path, info.Mode().Perm())
+			if err != nil {
+				err = fmt.Errorf("failed to set mode of %s: %w", path, err)
+				return res, err
+			}
+		}
+
+	}
+
+	if hasManifest {
+		if m.Files == nil {
+			m.Files = make(map[string]string, len(changes))
+		}
+		for rel, data := range changes {
+			m.Files[rel] = HashContent(data)
+		}
+		m.Features = append(m.Features, f.Name)
+		sort.Strings(m.Features)
+		err = m.Write(staged, projDir)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	err = TmplWriter{OutFs: outFs}.commit(staged, projDir, nil)
+	if err != nil {
+		err = fmt.Errorf("failed to add feature %s: %w", f.Name, err)
+		return res, err
+	}
+
+	if !opts.Hooks {
+		return res, err
+	}
+
+	res.Hooks, err = runHooks(ctx, f.Hooks, outFs, projDir, vals)
+	if err != nil || !hasManifest {
+		return res, err
+	}
+
+	// Hooks such as go mod tidy rewrite what was just written.
+	err = rehashManifest(outFs, projDir, nil)
+	return res, err
+}
+
+// plan works out the content of every file the feature adds or changes, keyed by path relative to the project root,
+// noting them in res.
+func (f Feature) plan(outFs afero.Fs, projDir string, vals map[string]any, res *AddResult) (changes map[string][]byte, err error) {
+	changes = make(map[string][]byte)
+	w := TmplWriter{TmplVals: vals}
+	if f.Fs != nil {
+		w, err = NewTmplWriterFromFs(afero.NewMemMapFs(), f.Fs, f.Root, f.Name, vals)
+		if err != nil {
+			err = fmt.Errorf("failed to create template writer for feature %s: %w", f.Name, err)
+			return changes, err
+		}
+
+		staged, renderErr := w.RenderProject("/")
+		if renderErr != nil {
+			return changes, renderErr
+		}
+
+		changes, err = readTree(staged, filepath.Join("/", w.ProjectRoot()))
+		if err != nil {
+			return changes, err
+		}
+		changes = withoutManifest(changes)
+	}
+
+	var existing []string
+	for _, rel := range sortedKeys(changes) {
+		exists, existsErr := afero.Exists(outFs, filepath.Join(projDir, rel))
+		if existsErr != nil {
+			err = fmt.Errorf("failed to check for %s: %w", rel, existsErr)
+			return changes, err
+		}
+		if exists {
+			existing = append(existing, rel)
+			continue
+		}
+		res.Added = append(res.Added, rel)
+	}
+	if len(existing) > 0 {
+		err = fmt.Errorf("feature %s would overwrite existing files: %s", f.Name, strings.Join(existing, ", "))
+		return changes, err
+	}
+
+	for _, p := range f.Patches {
+		buf, resolveErr := w.ResolveTemplateVars(p.File)
+		if resolveErr != nil {
+			err = fmt.Errorf("failed to resolve patch path %s: %w", p.File, resolveErr)
+			return changes, err
+		}
+		rel := buf.String()
+
+		src, ok := changes[rel]
+		if !ok {
+			src, err = afero.ReadFile(outFs, filepath.Join(projDir, rel))
+			if err != nil {
+				err = fmt.Errorf("feature %s needs to %s, but can't read %s: %w", f.Name, p.Desc, rel, err)
+				return changes, err
+			}
+		}
+
+		out, changed, patchErr := p.Apply(rel, src)
+		if patchErr != nil {
+			err = fmt.Errorf("feature %s: %w", f.Name, patchErr)
+			return changes, err
+		}
+		if changed {
+			changes[rel] = out
+			if !slices.Contains(res.Added, rel) && !slices.Contains(res.Patched, rel) {
+				res.Patched = append(res.Patched, rel)
+			}
+		}
+	}
+
+	if len(f.Requires) == 0 {
+		return changes, err
+	}
+
+	gomod, required, err := addRequires(outFs, projDir, f.Requires)
+	if err != nil {
+		err = fmt.Errorf("feature %s: %w", f.Name, err)
+		return changes, err
+	}
+	if len(required) > 0 {
+		changes["go.mod"] = gomod
+		res.Patched = append(res.Patched, "go.mod")
+		res.Required = required
+	}
+
+	return changes, err
+}
+
+// addRequires adds any of reqs the project's go.mod doesn't already require, at any version, returning the new
+// go.mod and what was added.
+func addRequires(outFs afero.Fs, projDir string, reqs []module.Version) (gomod []byte, added []string, err error) {
+	modPath := filepath.Join(projDir, "go.mod")
+	data, err := afero.ReadFile(outFs, modPath)
+	if err != nil {
+		err = fmt.Errorf("failed to read %s: %w", modPath, err)
+		return gomod, added, err
+	}
+
+	mf, err := modfile.Parse(modPath, data, nil)
+	if err != nil {
+		err = fmt.Errorf("failed to parse %s: %w", modPath, err)
+		return gomod, added, err
+	}
+
+	for _, req := range reqs {
+		if slices.ContainsFunc(mf.Require, func(r *modfile.Require) bool { return r.Mod.Path == req.Path }) {
+			continue
+		}
+
+		err = mf.AddRequire(req.Path, req.Version)
+		if err != nil {
+			err = fmt.Errorf("failed to require %s: %w", req, err)
+			return gomod, added, err
+		}
+		added = append(added, req.String())
+	}
+
+	mf.Cleanup()
+	gomod, err = mf.Format()
+	if err != nil {
+		err = fmt.Errorf("failed to format %s: %w", modPath, err)
+		return gomod, added, err
+	}
+
+	return gomod, added, err
+}
+
+// loadManifestIfAny loads a project's manifest.  found is false if it has none.
+func loadManifestIfAny(outFs afero.Fs, projDir string) (m Manifest, found bool, err error) {
+	_, err = outFs.Stat(filepath.Join(projDir, ManifestFileName))
+	if os.IsNotExist(err) {
+		return m, false, nil
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to check for manifest: %w", err)
+		return m, false, err
+	}
+
+	m, err = LoadManifest(outFs, projDir)
+	return m, err == nil, err
+}
+
+// ProjectValues works out the values an existing project was generated from.  They come from its manifest if it has
+// one.  Otherwise, the basics are read from its go.mod: ProjectPackage is the module path, ProjectName its last
+// element, ProjectPackageName that name made safe for a Go package, and GolangVersion the go directive.  go.mod also
+// fills in any of those a manifest lacks, e.g. for projects generated from a template spec.
+func ProjectValues(outFs afero.Fs, projDir string) (vals map[string]any, err error) {
+	m, _, err := loadManifestIfAny(outFs, projDir)
+	if err != nil {
+		return vals, err
+	}
+
+	return projectValues(outFs, projDir, m)
+}
+
+func projectValues(outFs afero.Fs, projDir string, m Manifest) (vals map[string]any, err error) {
+	vals = make(map[string]any, len(m.Values)+4)
+	for k, v := range m.Values {
+		vals[k] = v
+	}
+
+	setDefault := func(key, val string) {
+		if s, ok := vals[key].(string); val != "" && (!ok || s == "") {
+			vals[key] = val
+		}
+	}
+
+	modPath := filepath.Join(projDir, "go.mod")
+	data, readErr := afero.ReadFile(outFs, modPath)
+	switch {
+	case readErr == nil:
+		mf, parseErr := modfile.ParseLax(modPath, data, nil)
+		if parseErr != nil {
+			err = fmt.Errorf("failed to parse %s: %w", modPath, parseErr)
+			return vals, err
+		}
+
+		if mf.Module != nil {
+			prefix, _, _ := module.SplitPathVersion(mf.Module.Mod.Path)
+			setDefault("ProjectPackage", mf.Module.Mod.Path)
+			setDefault("ProjectName", path.Base(prefix))
+		}
+		if mf.Go != nil {
+			setDefault("GolangVersion", mf.Go.Version)
+		}
+	case !os.IsNotExist(readErr):
+		err = fmt.Errorf("failed to read %s: %w", modPath, readErr)
+		return vals, err
+	}
+
+	name, _ := vals["ProjectName"].(string)
+	if name == "" {
+		err = fmt.Errorf("%s has neither a %s nor a go.mod to take the project's values from", projDir, ManifestFileName)
+		return vals, err
+	}
+	// The same as the built-in types' AsMap.
+	setDefault("ProjectPackageName", strings.ReplaceAll(name, "-", ""))
+
+	return vals, err
+}
+
+// invalidFeatureError reports a feature that isn't registered.
+func invalidFeatureError(name string) error {
+	names := make([]string, 0)
+	for _, f := range Features() {
+		names = append(names, f.Name)
+	}
+
+	return fmt.Errorf("%w %q: options are %s", ErrInvalidFeature, name, strings.Join(names, ", "))
+}
+
+// featureRegistry holds every registered feature, in registration order.
+var featureRegistry = struct { //nolint:gochecknoglobals // feature registry
+	sync.RWMutex
+	features []Feature
+}{}
+
+// RegisterFeature makes a feature available to AddFeature.  Programs importing this package can register their own,
+// typically from an init func.
+func RegisterFeature(f Feature) (err error) {
+	if f.Name == "" {
+		return errors.New("feature has no name")
+	}
+
+	if f.Fs == nil && len(f.Patches) == 0 {
+		return fmt.Errorf("feature %s neither adds files nor patches any", f.Name)
+	}
+
+	featureRegistry.Lock()
+	defer featureRegistry.Unlock()
+
+	for _, existing := range featureRegistry.features {
+		if existing.Name == f.Name {
+			return fmt.Errorf("feature %s is already registered", f.Name)
+		}
+	}
+
+	featureRegistry.features = append(featureRegistry.features, f)
+
+	return err
+}
+
+// LookupFeature returns the registered feature with the given name.
+func LookupFeature(name string) (f Feature, ok bool) {
+	featureRegistry.RLock()
+	defer featureRegistry.RUnlock()
+
+	for _, existing := range featureRegistry.features {
+		if existing.Name == name {
+			return existing, true
+		}
+	}
+
+	return f, false
+}
+
+// Features lists the registered features in registration order.
+func Features() []Feature {
+	featureRegistry.RLock()
+	defer featureRegistry.RUnlock()
+
+	return append([]Feature(nil), featureRegistry.features...)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"{{.ProjectPackage}}/pkg/grpcserver"
+)
+
+//nolint:gochecknoglobals // Cobra boilerplate
+var grpcAddress string
+
+// grpcCmd runs the gRPC server.  It is added to rootCmd in root.go.
+//
+//nolint:gochecknoglobals // Cobra boilerplate
+var grpcCmd = &cobra.Command{
+	Use:   "grpc",
+	Short: "Run the gRPC server",
+	Long: `
+Run the {{.ProjectName}} gRPC server until interrupted.  It serves the standard health service and server reflection,
+along with the services registered in cmd/grpc.go.
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		server, _ := grpcserver.New()
+		// Register your services here, e.g. pb.RegisterGreeterServer(server, &greeter{}).
+
+		err = grpcserver.Serve(ctx, server, grpcAddress)
+		return err
+	},
+}
+
+//nolint:gochecknoinits // Cobra boilerplate
+func init() {
+	grpcCmd.Flags().StringVarP(&grpcAddress, "address", "a", ":50051", "Address to serve gRPC on")
+}
@@ -0,0 +1,54 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// New returns a gRPC server with the standard health service and server reflection registered.  Register your own
+// services on the server before calling Serve, and use the health server to report their status.
+func New(opts ...grpc.ServerOption) (server *grpc.Server, healthServer *health.Server) {
+	server = grpc.NewServer(opts...)
+
+	healthServer = health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return server, healthServer
+}
+
+// Serve serves on addr until ctx is done, then stops gracefully, letting calls in flight finish.
+func Serve(ctx context.Context, server *grpc.Server, addr string) (err error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		err = fmt.Errorf("failed to listen on %s: %w", addr, err)
+		return err
+	}
+
+	stopped := make(chan struct{})
+	defer close(stopped)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			server.GracefulStop()
+		case <-stopped:
+		}
+	}()
+
+	err = server.Serve(listener)
+	if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		err = fmt.Errorf("gRPC server failed: %w", err)
+		return err
+	}
+
+	err = nil
+	return err
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"{{.ProjectPackage}}/pkg/metrics"
+)
+
+//nolint:gochecknoglobals // Cobra boilerplate
+var metricsAddress string
+
+// metricsCmd serves Prometheus metrics.  It is added to rootCmd in root.go.
+//
+//nolint:gochecknoglobals // Cobra boilerplate
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Serve Prometheus metrics",
+	Long: `
+Serve the metrics exported by {{.ProjectName}} in Prometheus format at /metrics.
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		err = metrics.Serve(metricsAddress)
+		return err
+	},
+}
+
+//nolint:gochecknoinits // Cobra boilerplate
+func init() {
+	metricsCmd.Flags().StringVarP(&metricsAddress, "address", "a", ":9090", "Address to serve metrics on")
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric {{.ProjectName}} exports.
+//
+//nolint:gochecknoglobals // shared metrics registry
+var Registry = prometheus.NewRegistry()
+
+// RunsTotal counts runs of {{.ProjectName}}, by command and result.
+//
+//nolint:gochecknoglobals // shared metrics registry
+var RunsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "{{snake .ProjectName}}_runs_total",
+		Help: "Total number of runs by command and result",
+	},
+	[]string{"command", "result"},
+)
+
+//nolint:gochecknoinits // metrics registration
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		RunsTotal,
+	)
+}
+
+// Serve exposes the registry in Prometheus format at /metrics on addr.  It only returns if the server fails.
+func Serve(addr string) (err error) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	err = server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		err = fmt.Errorf("metrics server failed: %w", err)
+		return err
+	}
+
+	err = nil
+	return err
+}
@@ -0,0 +1,121 @@
+package {{.ProjectPackageName}}
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"go.uber.org/zap"
+)
+
+// Environment variables configuring the OIDC guard.  Tokens must be issued by OIDC_ISSUER for OIDC_CLIENT_ID.
+const (
+	OIDCIssuerEnv   = "OIDC_ISSUER"
+	OIDCClientIDEnv = "OIDC_CLIENT_ID"
+)
+
+// ErrOIDCNotConfigured is returned when the issuer or client ID aren't set.
+var ErrOIDCNotConfigured = errors.New(OIDCIssuerEnv + " and " + OIDCClientIDEnv + " must both be set")
+
+// claimsKey is the context key the verified token's claims are stored under.
+type claimsKey struct{}
+
+// oidcVerifier caches the verifier once the issuer has been discovered.  Discovery is retried until it succeeds, so
+// an issuer that is briefly unreachable at startup doesn't disable authentication for good.
+//
+//nolint:gochecknoglobals // discovered once per process
+var oidcVerifier = struct {
+	sync.Mutex
+	verifier *oidc.IDTokenVerifier
+}{}
+
+// idTokenVerifier returns a verifier for ID tokens from the configured issuer, discovering it on first use.
+func idTokenVerifier() (verifier *oidc.IDTokenVerifier, err error) {
+	oidcVerifier.Lock()
+	defer oidcVerifier.Unlock()
+
+	if oidcVerifier.verifier != nil {
+		verifier = oidcVerifier.verifier
+		return verifier, err
+	}
+
+	issuer, clientID := os.Getenv(OIDCIssuerEnv), os.Getenv(OIDCClientIDEnv)
+	if issuer == "" || clientID == "" {
+		err = ErrOIDCNotConfigured
+		return verifier, err
+	}
+
+	// The provider keeps this context to fetch signing keys later, so it mustn't be a request's.
+	provider, err := oidc.NewProvider(context.Background(), issuer)
+	if err != nil {
+		err = fmt.Errorf("failed to discover OIDC issuer %s: %w", issuer, err)
+		return verifier, err
+	}
+
+	verifier = provider.Verifier(&oidc.Config{ClientID: clientID})
+	oidcVerifier.verifier = verifier
+
+	return verifier, err
+}
+
+// requireOIDC only lets through requests bearing a valid ID token from the configured issuer.  next can read the
+// token's claims with ClaimsFromContext.
+func (s *Server) requireOIDC(next http.HandlerFunc) (handler http.HandlerFunc) {
+	handler = func(w http.ResponseWriter, r *http.Request) {
+		verifier, err := idTokenVerifier()
+		if err != nil {
+			s.logger.Error("OIDC verifier unavailable", zap.Error(err))
+			http.Error(w, "authentication unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || raw == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		token, err := verifier.Verify(r.Context(), raw)
+		if err != nil {
+			s.logger.Debug("Rejected token", zap.Error(err))
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		claims := make(map[string]any)
+		err = token.Claims(&claims)
+		if err != nil {
+			s.logger.Error("Failed to read token claims", zap.Error(err))
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims)))
+	}
+	return handler
+}
+
+// ClaimsFromContext returns the claims of the token requireOIDC verified for a request.
+func ClaimsFromContext(ctx context.Context) (claims map[string]any, ok bool) {
+	claims, ok = ctx.Value(claimsKey{}).(map[string]any)
+	return claims, ok
+}
+
+// whoamiHandler returns the claims of the caller's token.
+func (s *Server) whoamiHandler(w http.ResponseWriter, r *http.Request) {
+	claims, _ := ClaimsFromContext(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(claims)
+	if err != nil {
+		s.logger.Error("Failed to write whoami response", zap.Error(err))
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"{{.ProjectPackage}}/pkg/database"
+)
+
+//nolint:gochecknoglobals // Cobra boilerplate
+var databaseURL string
+
+// dbCmd checks the Postgres connection.  It is added to rootCmd in root.go.
+//
+//nolint:gochecknoglobals // Cobra boilerplate
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Check the Postgres connection",
+	Long: `
+Connect to the Postgres database {{.ProjectName}} is configured to use, and check that it answers.
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		db, err := database.Open(cmd.Context(), databaseURL)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		fmt.Println("Database is reachable.")
+		return err
+	},
+}
+
+//nolint:gochecknoinits // Cobra boilerplate
+func init() {
+	dbCmd.Flags().StringVar(&databaseURL, "database-url", "", fmt.Sprintf("Postgres connection URL (default $%s)", database.URLEnv))
+}
@@ -0,0 +1,220 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateForFeature generates a project of the given type in memory, returning its directory.
+func generateForFeature(t *testing.T, afs afero.Fs, projType string) string {
+	t.Helper()
+
+	res, err := Generate(context.Background(), Options{Type: projType, Values: goldenAnswers(), OutFs: afs, Dest: "/out"})
+	require.NoError(t, err)
+
+	return res.ProjectDir
+}
+
+func TestAddFeature(t *testing.T) {
+	ctx := context.Background()
+	afs := afero.NewMemMapFs()
+	projDir := generateForFeature(t, afs, CobraProjectType)
+	opts := AddOptions{Feature: FeatureMetrics, OutFs: afs, ProjectDir: projDir}
+
+	dryRun := opts
+	dryRun.DryRun = true
+	res, err := AddFeature(ctx, dryRun)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cmd/metrics.go", "pkg/metrics/metrics.go"}, res.Added)
+	assert.Equal(t, []string{"cmd/root.go", "go.mod"}, res.Patched)
+	assert.Equal(t, []string{"github.com/prometheus/client_golang@v1.20.5"}, res.Required)
+	exists, err := afero.Exists(afs, filepath.Join(projDir, "cmd/metrics.go"))
+	require.NoError(t, err)
+	assert.False(t, exists, "dry run wrote files")
+
+	res, err = AddFeature(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, CobraProjectType, res.ProjectType)
+
+	metricsCmd, err := afero.ReadFile(afs, filepath.Join(projDir, "cmd/metrics.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(metricsCmd), `"github.com/example/demo/pkg/metrics"`)
+
+	root, err := afero.ReadFile(afs, filepath.Join(projDir, "cmd/root.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(root), "rootCmd.AddCommand(metricsCmd)")
+
+	gomod, err := afero.ReadFile(afs, filepath.Join(projDir, "go.mod"))
+	require.NoError(t, err)
+	assert.Contains(t, string(gomod), "github.com/prometheus/client_golang v1.20.5")
+
+	m, err := LoadManifest(afs, projDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{FeatureMetrics}, m.Features)
+
+	// Everything the feature wrote is boilerplate's, not the user's.
+	for _, rel := range append(res.Added, res.Patched...) {
+		data, readErr := afero.ReadFile(afs, filepath.Join(projDir, rel))
+		require.NoError(t, readErr)
+		assert.Equal(t, HashContent(data), m.Files[rel], rel)
+	}
+
+	_, err = AddFeature(ctx, opts)
+	assert.ErrorContains(t, err, "already been added")
+
+	// Updating the project to templates that have moved on keeps track of its features, and carries the patches over.
+	base, err := ReadBase(afs, projDir)
+	require.NoError(t, err)
+	base["cmd/root.go"] = []byte(strings.Replace(string(base["cmd/root.go"]), "package cmd\n", "package cmd // old\n", 1))
+	require.NoError(t, WriteBase(afs, projDir, base))
+	require.NoError(t, m.Write(afs, projDir))
+
+	result, err := UpdateProject(afs, projDir, ConflictMarkers)
+	require.NoError(t, err)
+	assert.Empty(t, result.Kept)
+	assert.Empty(t, result.Conflicts)
+	assert.Equal(t, []string{"cmd/root.go"}, result.Updated)
+
+	root, err = afero.ReadFile(afs, filepath.Join(projDir, "cmd/root.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(root), "rootCmd.AddCommand(metricsCmd)")
+
+	m, err = LoadManifest(afs, projDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{FeatureMetrics}, m.Features)
+}
+
+func TestAddFeature_Errors(t *testing.T) {
+	ctx := context.Background()
+	afs := afero.NewMemMapFs()
+	projDir := generateForFeature(t, afs, CobraProjectType)
+
+	_, err := AddFeature(ctx, AddOptions{Feature: "nope", OutFs: afs, ProjectDir: projDir})
+	assert.ErrorIs(t, err, ErrInvalidFeature)
+
+	_, err = AddFeature(ctx, AddOptions{Feature: FeatureHelm, OutFs: afs, ProjectDir: projDir})
+	assert.ErrorContains(t, err, "feature helm is for headless-service, indirect-selection projects, not cobra")
+
+	// Nothing is written if a file the feature adds is already there.
+	require.NoError(t, afero.WriteFile(afs, filepath.Join(projDir, "Dockerfile"), []byte("FROM scratch\n"), 0644))
+	_, err = AddFeature(ctx, AddOptions{Feature: FeatureDockerfile, OutFs: afs, ProjectDir: projDir})
+	assert.ErrorContains(t, err, "would overwrite existing files: Dockerfile")
+	exists, err := afero.Exists(afs, filepath.Join(projDir, ".dockerignore"))
+	require.NoError(t, err)
+	assert.False(t, exists, "feature partly added")
+
+	// Nor if writing fails part way, here because a file is in the way of a directory.
+	manifest, err := afero.ReadFile(afs, filepath.Join(projDir, ManifestFileName))
+	require.NoError(t, err)
+	rootGo, err := afero.ReadFile(afs, filepath.Join(projDir, "cmd/root.go"))
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(afs, filepath.Join(projDir, "pkg/metrics"), []byte("in the way\n"), 0644))
+	_, err = AddFeature(ctx, AddOptions{Feature: FeatureMetrics, OutFs: afs, ProjectDir: projDir})
+	assert.ErrorContains(t, err, "a file is in the way")
+	exists, err = afero.Exists(afs, filepath.Join(projDir, "cmd/metrics.go"))
+	require.NoError(t, err)
+	assert.False(t, exists, "feature partly added")
+	after, err := afero.ReadFile(afs, filepath.Join(projDir, "cmd/root.go"))
+	require.NoError(t, err)
+	assert.Equal(t, string(rootGo), string(after))
+	after, err = afero.ReadFile(afs, filepath.Join(projDir, ManifestFileName))
+	require.NoError(t, err)
+	assert.Equal(t, string(manifest), string(after))
+
+	// Nor if an anchor is missing.
+	require.NoError(t, afs.Remove(filepath.Join(projDir, "cmd/root.go")))
+	_, err = AddFeature(ctx, AddOptions{Feature: FeatureGRPCServer, OutFs: afs, ProjectDir: projDir})
+	assert.ErrorContains(t, err, "can't read cmd/root.go")
+	exists, err = afero.Exists(afs, filepath.Join(projDir, "cmd/grpc.go"))
+	require.NoError(t, err)
+	assert.False(t, exists, "feature partly added")
+
+	_, err = AddFeature(ctx, AddOptions{Feature: FeatureDockerfile, OutFs: afs, ProjectDir: "/empty"})
+	assert.ErrorContains(t, err, "neither a .boilerplate.json nor a go.mod")
+}
+
+func TestProjectValues_GoMod(t *testing.T) {
+	afs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(afs, "/proj/go.mod", []byte("module github.com/example/my-tool/v2\n\ngo 1.22\n"), 0644))
+
+	vals, err := ProjectValues(afs, "/proj")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"ProjectPackage":     "github.com/example/my-tool/v2",
+		"ProjectName":        "my-tool",
+		"ProjectPackageName": "mytool",
+		"GolangVersion":      "1.22",
+	}, vals)
+
+	// Without a manifest, the project's type isn't known, so any feature is let through.
+	res, err := AddFeature(context.Background(), AddOptions{Feature: FeatureDockerfile, OutFs: afs, ProjectDir: "/proj"})
+	require.NoError(t, err)
+	assert.Empty(t, res.ProjectType)
+
+	dockerfile, err := afero.ReadFile(afs, "/proj/Dockerfile")
+	require.NoError(t, err)
+	assert.Contains(t, string(dockerfile), "FROM golang:1.22 AS builder")
+	assert.Contains(t, string(dockerfile), `ENTRYPOINT ["/app/my-tool"]`)
+
+	exists, err := afero.Exists(afs, "/proj/"+ManifestFileName)
+	require.NoError(t, err)
+	assert.False(t, exists, "manifest created")
+}
+
+// TestBuiltinFeatures adds every built-in feature to every project type it is for, and checks the Go it writes
+// parses.
+func TestBuiltinFeatures(t *testing.T) {
+	for _, f := range Features() {
+		for _, projType := range f.Types {
+			t.Run(f.Name+"/"+projType, func(t *testing.T) {
+				afs := afero.NewMemMapFs()
+				projDir := generateForFeature(t, afs, projType)
+
+				res, err := AddFeature(context.Background(), AddOptions{Feature: f.Name, OutFs: afs, ProjectDir: projDir})
+				require.NoError(t, err)
+				assert.NotEmpty(t, res.Added)
+
+				for _, rel := range append(res.Added, res.Patched...) {
+					if !strings.HasSuffix(rel, ".go") {
+						continue
+					}
+
+					src, readErr := afero.ReadFile(afs, filepath.Join(projDir, rel))
+					require.NoError(t, readErr)
+					_, parseErr := parser.ParseFile(token.NewFileSet(), rel, src, parser.AllErrors)
+					assert.NoError(t, parseErr, rel)
+				}
+			})
+		}
+	}
+}
+
+func TestRegisterFeature(t *testing.T) {
+	assert.Error(t, RegisterFeature(Feature{}))
+	assert.ErrorContains(t, RegisterFeature(Feature{Name: "empty"}), "neither adds files nor patches any")
+
+	err := RegisterFeature(Feature{Name: FeatureMetrics, Patches: []GoPatch{AddSubcommandPatch(rootCmdFile, "rootCmd", "x")}})
+	assert.ErrorContains(t, err, "already registered")
+
+	_, ok := LookupFeature(FeatureHelm)
+	assert.True(t, ok)
+	_, ok = LookupFeature("nope")
+	assert.False(t, ok)
+}
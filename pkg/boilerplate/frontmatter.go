@@ -0,0 +1,77 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// frontMatterRe matches a directive on the first line of a template file, written as a comment in whatever syntax
+// suits the file, e.g.
+//
+//	# boilerplate: delims=[[ ]]
+//	// boilerplate: verbatim
+//	<!-- boilerplate: delims=<% %> -->
+var frontMatterRe = regexp.MustCompile(`^\s*(?:#|//|/\*|<!--)\s*boilerplate:\s*(.*?)\s*(?:\*/|-->)?\s*$`) //nolint:gochecknoglobals // compiled once
+
+// FileDirectives controls how a single template file is rendered.
+type FileDirectives struct {
+	// Delims replaces the {{ }} template delimiters for this file.
+	Delims [2]string
+	// Verbatim copies the file without evaluating it as a template.
+	Verbatim bool
+}
+
+// parseFrontMatter splits a front-matter directive line off the top of a template file.  The line is dropped from
+// the output.  found is false, and body is data unchanged, if the file has no directive.
+func parseFrontMatter(data string) (dirs FileDirectives, body string, found bool, err error) {
+	first, rest, _ := strings.Cut(data, "\n")
+	m := frontMatterRe.FindStringSubmatch(strings.TrimSuffix(first, "\r"))
+	if m == nil {
+		return dirs, data, false, err
+	}
+
+	for _, opt := range splitDirectives(m[1]) {
+		key, val, _ := strings.Cut(opt, "=")
+		switch key {
+		case "verbatim":
+			dirs.Verbatim = true
+		case "delims":
+			fields := strings.Fields(val)
+			if len(fields) != 2 {
+				err = fmt.Errorf("delims must be a left and right delimiter separated by a space, e.g. delims=[[ ]]: %q", val)
+				return dirs, data, false, err
+			}
+			dirs.Delims = [2]string{fields[0], fields[1]}
+		default:
+			err = fmt.Errorf("unknown boilerplate directive %q", key)
+			return dirs, data, false, err
+		}
+	}
+
+	return dirs, rest, true, err
+}
+
+// splitDirectives splits "delims=[[ ]] verbatim" into its options, keeping the space in a delims value.
+func splitDirectives(s string) (opts []string) {
+	fields := strings.Fields(s)
+	for i := 0; i < len(fields); i++ {
+		opt := fields[i]
+		if strings.HasPrefix(opt, "delims=") && i+1 < len(fields) && !strings.Contains(fields[i+1], "=") && fields[i+1] != "verbatim" {
+			opt += " " + fields[i+1]
+			i++
+		}
+		opts = append(opts, opt)
+	}
+
+	return opts
+}
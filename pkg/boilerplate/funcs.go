@@ -0,0 +1,208 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"go/token"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// nowFunc is the clock behind the now and year template functions.
+var nowFunc = time.Now //nolint:gochecknoglobals // overridden in tests
+
+// TemplateFuncs returns the functions available to every template, path, default, derived value and file rule.
+func TemplateFuncs() template.FuncMap {
+	funcs := template.FuncMap{
+		"camel":          camelCase,
+		"pascal":         pascalCase,
+		"snake":          func(s string) string { return strings.Join(lowerWords(s), "_") },
+		"kebab":          func(s string) string { return strings.Join(lowerWords(s), "-") },
+		"screamingSnake": func(s string) string { return strings.ToUpper(strings.Join(lowerWords(s), "_")) },
+		"title":          titleCase,
+		"plural":         plural,
+		"lower":          strings.ToLower,
+		"upper":          strings.ToUpper,
+		"quote":          strconv.Quote,
+		"indent":         indent,
+		"default":        defaultValue,
+		"goIdent":        goIdent,
+	}
+
+	for name, f := range clockFuncs(func() time.Time { return nowFunc() }) {
+		funcs[name] = f
+	}
+
+	return funcs
+}
+
+// clockFuncs returns the now and year template functions for a clock.
+func clockFuncs(now func() time.Time) template.FuncMap {
+	return template.FuncMap{
+		"now":  now,
+		"year": func() string { return strconv.Itoa(now().Year()) },
+	}
+}
+
+// splitWords breaks a name into words at punctuation, spaces, and case changes, so "myHTTPServer", "my-http-server"
+// and "My HTTP server" all give my, HTTP, server.
+func splitWords(s string) (words []string) {
+	runes := []rune(s)
+	start := -1
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+		nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower)) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
+
+func lowerWords(s string) []string {
+	words := splitWords(s)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+
+	return words
+}
+
+func upperFirst(s string) string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return s
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+
+	return string(runes)
+}
+
+func pascalCase(s string) string {
+	var sb strings.Builder
+	for _, w := range lowerWords(s) {
+		sb.WriteString(upperFirst(w))
+	}
+
+	return sb.String()
+}
+
+func camelCase(s string) string {
+	words := lowerWords(s)
+	for i := 1; i < len(words); i++ {
+		words[i] = upperFirst(words[i])
+	}
+
+	return strings.Join(words, "")
+}
+
+// titleCase capitalizes the first letter of every space separated word, leaving the rest alone.
+func titleCase(s string) string {
+	fields := strings.Split(s, " ")
+	for i, f := range fields {
+		fields[i] = upperFirst(f)
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// plural applies the regular English plural rules, which is good enough for resource names.
+func plural(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case s == "":
+		return s
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return s[:len(s)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return s + "es"
+	}
+
+	return s + "s"
+}
+
+// indent prefixes every non-empty line with n spaces.
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = pad + l
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// defaultValue returns def when val is missing or empty, so it reads naturally in a pipeline: .Port | default "8080".
+// Writers fail on values that don't exist at all, so optional ones are looked up with index: index . "Port".
+func defaultValue(def any, val ...any) any {
+	if len(val) == 0 || val[0] == nil {
+		return def
+	}
+
+	if s, ok := val[0].(string); ok && s == "" {
+		return def
+	}
+
+	return val[0]
+}
+
+// goIdent turns a name into a valid Go identifier by dropping the characters Go doesn't allow, the same way
+// ProjectPackageName drops the dashes from ProjectName.
+func goIdent(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			sb.WriteRune(r)
+		}
+	}
+
+	ident := sb.String()
+	switch {
+	case ident == "":
+		return "_"
+	case unicode.IsDigit([]rune(ident)[0]):
+		ident = "_" + ident
+	case token.IsKeyword(ident):
+		ident += "_"
+	}
+
+	return ident
+}
+
+// newTemplate creates a template with the template functions installed.
+func newTemplate(name string) *template.Template {
+	return template.New(name).Funcs(TemplateFuncs())
+}
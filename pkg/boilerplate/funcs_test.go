@@ -0,0 +1,55 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	nowFunc = func() time.Time { return time.Date(2031, 5, 4, 0, 0, 0, 0, time.UTC) }
+	defer func() { nowFunc = time.Now }()
+
+	w := TmplWriter{TmplVals: map[string]any{
+		"ProjectName": "my-http-server",
+		"Empty":       "",
+		"Resource":    "policy",
+		"Body":        "a\n\nb",
+	}}
+
+	for _, tc := range []struct {
+		Tmpl string
+		Want string
+	}{
+		{Tmpl: `{{camel .ProjectName}}`, Want: "myHttpServer"},
+		{Tmpl: `{{pascal .ProjectName}}`, Want: "MyHttpServer"},
+		{Tmpl: `{{snake .ProjectName}}`, Want: "my_http_server"},
+		{Tmpl: `{{kebab "myHTTPServer"}}`, Want: "my-http-server"},
+		{Tmpl: `{{screamingSnake .ProjectName}}`, Want: "MY_HTTP_SERVER"},
+		{Tmpl: `{{title "an example tool"}}`, Want: "An Example Tool"},
+		{Tmpl: `{{plural .Resource}} {{plural "box"}} {{plural "key"}} {{plural "user"}}`, Want: "policies boxes keys users"},
+		{Tmpl: `{{lower "ABC"}}{{upper "def"}}`, Want: "abcDEF"},
+		{Tmpl: `{{quote .ProjectName}}`, Want: `"my-http-server"`},
+		{Tmpl: `{{indent 2 .Body}}`, Want: "  a\n\n  b"},
+		{Tmpl: `{{.Empty | default "fallback"}} {{.ProjectName | default "fallback"}} {{index . "Missing" | default "none"}}`, Want: "fallback my-http-server none"},
+		{Tmpl: `{{year}} {{(now).Month}}`, Want: "2031 May"},
+		{Tmpl: `{{goIdent .ProjectName}} {{goIdent "9lives"}} {{goIdent "type"}}`, Want: "myhttpserver _9lives type_"},
+	} {
+		t.Run(tc.Tmpl, func(t *testing.T) {
+			buf, err := w.ResolveTemplateVars(tc.Tmpl)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Want, buf.String())
+		})
+	}
+}
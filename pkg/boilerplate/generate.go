@@ -0,0 +1,302 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+)
+
+// ErrInvalidType is returned for a project type that isn't registered.
+var ErrInvalidType = errors.New("invalid project type")
+
+// Options say what Generate should generate, and where.
+type Options struct {
+	// Type names a registered project type.  It can be left out if Templates declares its own in a boilerplate.yaml.
+	Type string
+
+	// Templates, if set, replaces the type's own template tree.  It has the same shape, i.e. {{.ProjectName}}/...
+	Templates fs.FS
+
+	// Source says where Templates came from, and is recorded in the project's manifest.
+	Source string
+
+	// Values answers the type's prompts, keyed by prompt name.
+	Values map[string]string
+
+	// OutFs is where the project is written.  The default is the OS file system.
+	OutFs afero.Fs
+
+	// Dest is the directory the project is created in.  The default is the current directory.
+	Dest string
+
+	// Defaults, keyed by prompt, replace the type's own defaults, e.g. with the user's from UserDefaults.Values.
+	Defaults map[string]string
+
+	// Prompter asks for whatever Values doesn't answer.  Without one, unanswered prompts fall back to their defaults.
+	Prompter Prompter
+
+	// OnConflict says what to do with files already in Dest.  The default is OnConflictFail.
+	OnConflict ConflictPolicy
+
+	// Verify checks the generated Go code once it is written, before any hooks run.
+	Verify bool
+
+	// Hooks runs the project type's hooks once the project is written.
+	Hooks bool
+
+	// Archive says the project will be packed into an archive rather than used where it is written, so the hooks that
+	// set up a working copy, git-init and pre-commit, are left out.  A repository has no place in an archive.
+	Archive bool
+
+	// AllowCommandHooks lets hooks that run shell commands, e.g. those declared by a boilerplate.yaml, run.  Without
+	// it they are skipped, as templates from elsewhere shouldn't run commands unasked.
+	AllowCommandHooks bool
+
+	// Log, if set, is given the command of each command hook before it runs.
+	Log io.Writer
+}
+
+// Result reports what Generate did.
+type Result struct {
+	Type string
+
+	// ProjectDir is the root of the generated project in OutFs.
+	ProjectDir string
+
+	// Values are the answers the project was generated from, less any secrets.
+	Values map[string]any
+
+	// Files lists every file, relative to Dest, and what was done with it.
+	Files []PlannedFile
+
+	// Hooks reports on each hook run.
+	Hooks []HookResult
+
+	// Warnings note anything done that the caller may not expect, e.g. an existing file left alone.
+	Warnings []string
+}
+
+// Generate creates a project, as the gen command does.  It never exits the process, and writes nothing to stdout
+// itself.  Errors to expect include ErrInvalidType, ValidationErrors (and so ValidationError) for bad answers, a
+// *TemplateError for a template that fails, a *CollisionError for existing files, and a *VerifyError.  If a hook
+// fails, the project has been written, and the result says which hook failed and why.
+func Generate(ctx context.Context, opts Options) (res Result, err error) {
+	pt, w, err := prepare(ctx, opts)
+	if err != nil {
+		return res, err
+	}
+
+	res.Type = pt.Name
+	res.Values = withoutKeys(w.TmplVals, w.Secrets)
+
+	err = ctx.Err()
+	if err != nil {
+		return res, err
+	}
+
+	res.Files, err = w.buildProject(opts.dest())
+	if err != nil {
+		return res, err
+	}
+	res.ProjectDir = filepath.Join(opts.dest(), w.ProjectRoot())
+
+	for _, f := range res.Files {
+		switch f.Action {
+		case ActionSkip:
+			res.Warnings = append(res.Warnings, fmt.Sprintf("%s already exists, and was left alone", f.TemplPath))
+		case ActionBackup:
+			res.Warnings = append(res.Warnings, fmt.Sprintf("%s already existed, and was backed up", f.TemplPath))
+		}
+	}
+
+	if opts.Verify {
+		modulePath, _ := res.Values["ProjectPackage"].(string)
+		err = VerifyProject(w.OutFs, res.ProjectDir, modulePath)
+		if err != nil {
+			return res, fmt.Errorf("generated project failed verification: %w", err)
+		}
+	}
+
+	if !opts.Hooks {
+		return res, err
+	}
+
+	err = ctx.Err()
+	if err != nil {
+		return res, err
+	}
+
+	hooks := pt.Hooks
+	if opts.Archive {
+		hooks = slices.DeleteFunc(slices.Clone(hooks), func(h Hook) bool {
+			return h.Name == HookGitInit || h.Name == HookPreCommit
+		})
+	}
+	hooks = guardCommandHooks(hooks, opts.AllowCommandHooks, opts.Log)
+
+	// Hooks such as gofmt rewrite files the manifest has already hashed, so it is re-hashed once they have run, and
+	// before git-init commits it.
+	rehashed := false
+	rehash := func() error {
+		rehashed = true
+		return rehashManifest(w.OutFs, res.ProjectDir, res.Files)
+	}
+	for i, h := range hooks {
+		if h.Name != HookGitInit {
+			continue
+		}
+
+		run := h.Run
+		hooks[i].Run = func(ctx context.Context, outFs afero.Fs, projDir string, vals map[string]any) error {
+			rehashErr := rehash()
+			if rehashErr != nil {
+				return rehashErr
+			}
+
+			return run(ctx, outFs, projDir, vals)
+		}
+	}
+
+	res.Hooks, err = runHooks(ctx, hooks, w.OutFs, res.ProjectDir, w.TmplVals)
+
+	if !rehashed {
+		rehashErr := rehash()
+		if rehashErr != nil {
+			return res, rehashErr
+		}
+	}
+
+	for _, h := range res.Hooks {
+		if h.Status == HookSkipped {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("hook %s %v", h.Name, h.Err))
+		}
+	}
+
+	return res, err
+}
+
+// PrepareWriter gets the answers and sets up the writer Generate would use, for callers that want to look before they
+// leap, e.g. with Plan or Diff.
+func PrepareWriter(ctx context.Context, opts Options) (w TmplWriter, err error) {
+	_, w, err = prepare(ctx, opts)
+	return w, err
+}
+
+// prepare finds the project type, gets the answers, and sets up a writer for them.
+func prepare(ctx context.Context, opts Options) (pt ProjectType, w TmplWriter, err error) {
+	err = ctx.Err()
+	if err != nil {
+		return pt, w, err
+	}
+
+	pt, err = opts.projectType()
+	if err != nil {
+		return pt, w, err
+	}
+
+	params, err := pt.AskParams(opts.Values, opts.Defaults, opts.Prompter)
+	if err != nil {
+		return pt, w, err
+	}
+
+	vals, err := params.AsMap()
+	if err != nil {
+		return pt, w, fmt.Errorf("failed to export params as map: %w", err)
+	}
+
+	err = ctx.Err()
+	if err != nil {
+		return pt, w, err
+	}
+
+	outFs := opts.OutFs
+	if outFs == nil {
+		outFs = afero.NewOsFs()
+	}
+
+	w, err = NewTmplWriterFromFs(outFs, pt.Fs, pt.Root, pt.Name, vals)
+	if err != nil {
+		return pt, w, err
+	}
+	w.FileRules = append(w.FileRules, pt.FileRules...)
+	w.Source = opts.Source
+	w.OnConflict = opts.OnConflict
+	w.Secrets = pt.SecretKeys()
+
+	return pt, w, err
+}
+
+// projectType finds the project type to generate: the one Templates declares, if it does, or else the registered
+// type named, with its templates replaced by Templates if given.
+func (opts Options) projectType() (pt ProjectType, err error) {
+	if opts.Templates != nil {
+		spec, specErr := LoadTemplateSpec(opts.Templates, ".")
+		switch {
+		case specErr == nil:
+			if opts.Type != "" && opts.Type != spec.Name {
+				return pt, fmt.Errorf("%w: the templates are for project type %q, not %q", ErrInvalidType, spec.Name, opts.Type)
+			}
+			return ProjectTypeFromSpec(spec, opts.Templates, "."), err
+		case !errors.Is(specErr, ErrNoTemplateSpec):
+			return pt, specErr
+		}
+	}
+
+	pt, ok := LookupProjectType(opts.Type)
+	if !ok {
+		return pt, invalidTypeError(opts.Type)
+	}
+
+	if opts.Templates != nil {
+		pt.Fs = opts.Templates
+		pt.Root = "."
+		pt.FileRules = nil
+	}
+
+	return pt, err
+}
+
+// dest is the directory Generate writes to.
+func (opts Options) dest() string {
+	if opts.Dest == "" {
+		return "."
+	}
+
+	return opts.Dest
+}
+
+// rehashManifest updates the hashes in a newly generated project's manifest to what is now on disk.  A manifest left
+// alone because it already existed isn't touched.
+func rehashManifest(outFs afero.Fs, projDir string, files []PlannedFile) (err error) {
+	for _, f := range files {
+		if filepath.Base(f.TemplPath) == ManifestFileName && f.Action == ActionSkip {
+			return err
+		}
+	}
+
+	m, err := LoadManifest(outFs, projDir)
+	if err != nil {
+		return err
+	}
+
+	err = m.Rehash(outFs, projDir)
+	if err != nil {
+		return fmt.Errorf("failed to update manifest after hooks: %w", err)
+	}
+
+	return m.Write(outFs, projDir)
+}
@@ -0,0 +1,182 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	afs := afero.NewMemMapFs()
+	opts := Options{Type: CobraProjectType, Values: goldenAnswers(), OutFs: afs, Dest: "/out", Hooks: true}
+
+	res, err := Generate(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, CobraProjectType, res.Type)
+	assert.Equal(t, "/out/demo", res.ProjectDir)
+	assert.Equal(t, "demo", res.Values["ProjectName"])
+	var files []string
+	for _, f := range res.Files {
+		assert.Equal(t, ActionCreate, f.Action, f.TemplPath)
+		files = append(files, f.TemplPath)
+	}
+	assert.Contains(t, files, "demo/"+ManifestFileName)
+	assert.NotEmpty(t, res.Hooks)
+	assert.Contains(t, res.Warnings, "hook go-mod-tidy skipped: go needs the project on disk")
+
+	exists, err := afero.Exists(afs, "/out/demo/main.go")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	_, err = Generate(context.Background(), opts)
+	var collision *CollisionError
+	assert.True(t, errors.As(err, &collision), "expected a collision, got %v", err)
+
+	opts.OnConflict = OnConflictSkip
+	res, err = Generate(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Contains(t, res.Warnings, "demo/main.go already exists, and was left alone")
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := Generate(ctx, Options{Type: "nope", OutFs: afero.NewMemMapFs()})
+	assert.ErrorIs(t, err, ErrInvalidType)
+
+	vals := goldenAnswers()
+	vals["MaintainerEmail"] = "not-an-email"
+	_, err = Generate(ctx, Options{Type: CobraProjectType, Values: vals, OutFs: afero.NewMemMapFs()})
+	var verr ValidationError
+	require.True(t, errors.As(err, &verr), "expected a validation error, got %v", err)
+	assert.Equal(t, ProjMaintainerEmail, verr.Key)
+
+	templFs := fstest.MapFS{
+		TemplateSpecFileName:     {Data: []byte("name: broken\nprompts: [{key: ProjectName}]\n")},
+		"{{.ProjectName}}/a.txt": {Data: []byte("one\n{{.Missing}}\n")},
+	}
+	_, err = Generate(ctx, Options{Templates: templFs, Values: map[string]string{"ProjectName": "x"}, OutFs: afero.NewMemMapFs()})
+	var terr *TemplateError
+	require.True(t, errors.As(err, &terr), "expected a template error, got %v", err)
+	assert.Equal(t, 2, terr.Line)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = Generate(canceled, Options{Type: CobraProjectType, Values: goldenAnswers(), OutFs: afero.NewMemMapFs()})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGenerate_Secrets(t *testing.T) {
+	templFs := fstest.MapFS{
+		TemplateSpecFileName:         {Data: []byte("name: secretive\nprompts: [{key: ProjectName}, {key: Token, type: secret}]\n")},
+		"{{.ProjectName}}/README.md": {Data: []byte("# {{.ProjectName}}\n")},
+		"{{.ProjectName}}/token.txt": {Data: []byte("{{.Token}}\n")},
+	}
+	afs := afero.NewMemMapFs()
+	vals := map[string]string{"ProjectName": "x", "Token": "hunter2"}
+	res, err := Generate(context.Background(), Options{Templates: templFs, Values: vals, OutFs: afs, Dest: "/out"})
+	require.NoError(t, err)
+	assert.NotContains(t, res.Values, "Token")
+
+	manifest, err := afero.ReadFile(afs, filepath.Join(res.ProjectDir, ManifestFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(manifest), "hunter2")
+	m, err := LoadManifest(afs, res.ProjectDir)
+	require.NoError(t, err)
+	assert.NotContains(t, m.Values, "Token")
+	assert.Equal(t, []string{"Token"}, m.Secrets)
+
+	// Update can't render the secret, so leaves what uses it alone.
+	result, err := UpdateProjectFromFs(afs, res.ProjectDir, templFs, ".", ConflictMarkers)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"token.txt"}, result.Kept)
+	assert.Equal(t, []string{"README.md"}, result.Unchanged)
+	token, err := afero.ReadFile(afs, filepath.Join(res.ProjectDir, "token.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2\n", string(token))
+}
+
+func TestGenerate_OnConflictSkip(t *testing.T) {
+	afs := afero.NewMemMapFs()
+	res, err := Generate(context.Background(), Options{Type: CobraProjectType, Values: goldenAnswers(), OutFs: afs, Dest: "/out"})
+	require.NoError(t, err)
+	require.NoError(t, WriteBase(afs, res.ProjectDir, map[string][]byte{"go.mod": []byte("kept\n")}))
+
+	// Everything is there already, so everything is planned as skipped, from the one rendering, and the manifest
+	// keeps its base.
+	again, err := Generate(context.Background(), Options{Type: CobraProjectType, Values: goldenAnswers(), OutFs: afs, Dest: "/out", OnConflict: OnConflictSkip})
+	require.NoError(t, err)
+	require.Equal(t, len(res.Files), len(again.Files))
+	for _, f := range again.Files {
+		assert.Equal(t, ActionSkip, f.Action, f.TemplPath)
+	}
+
+	base, err := ReadBase(afs, res.ProjectDir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"go.mod": []byte("kept\n")}, base)
+}
+
+// TestGenerate_HooksLeaveCleanTree checks that the manifest is re-hashed before git-init commits it, so a new project
+// starts out with nothing to commit.
+func TestGenerate_HooksLeaveCleanTree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	templFs := fstest.MapFS{
+		TemplateSpecFileName:       {Data: []byte("name: tidy\nprompts: [{key: ProjectName}, {key: MaintainerName}, {key: MaintainerEmail}]\nhooks: [gofmt, git-init]\n")},
+		"{{.ProjectName}}/main.go": {Data: []byte("package main\nfunc main(){\n}\n")},
+	}
+	vals := map[string]string{"ProjectName": "x", "MaintainerName": "Tester", "MaintainerEmail": "tester@example.com"}
+	res, err := Generate(context.Background(), Options{Templates: templFs, Values: vals, OutFs: afero.NewOsFs(), Dest: t.TempDir(), Hooks: true})
+	require.NoError(t, err)
+	for _, h := range res.Hooks {
+		require.NoError(t, h.Err, h.Name)
+	}
+
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = res.ProjectDir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	assert.Empty(t, string(out), "expected a clean work tree")
+}
+
+func TestGenerate_Prompter(t *testing.T) {
+	var asked []ParamPrompt
+	prompter := PrompterFunc(func(key ParamPrompt, p Prompt) (string, error) {
+		asked = append(asked, key)
+		if key == ProjMaintainerName {
+			return "Prompted", nil
+		}
+		return "", nil
+	})
+
+	vals := goldenAnswers()
+	delete(vals, "MaintainerName")
+	res, err := Generate(context.Background(), Options{Type: CobraProjectType, Values: vals, OutFs: afero.NewMemMapFs(), Prompter: prompter})
+	require.NoError(t, err)
+	assert.Equal(t, "Prompted", res.Values["MaintainerName"])
+	assert.Contains(t, asked, ProjShortDesc)
+	assert.NotContains(t, asked, ProjName)
+
+	_, err = Generate(context.Background(), Options{Type: CobraProjectType, OutFs: afero.NewMemMapFs(), Prompter: PrompterFunc(func(ParamPrompt, Prompt) (string, error) {
+		return "", errors.New("portal closed")
+	})})
+	assert.ErrorContains(t, err, "portal closed")
+}
@@ -0,0 +1,262 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha1" //nolint:gosec // git object ids are sha1
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GitSignature is the author and committer of a commit.
+type GitSignature struct {
+	Name  string
+	Email string
+	When  time.Time
+}
+
+// gitDefaultBranch is the branch gitInit commits to.
+const gitDefaultBranch = "main"
+
+// gitEntry is a file recorded in the initial commit.
+type gitEntry struct {
+	path string
+	mode uint32
+	id   [sha1.Size]byte
+	info os.FileInfo
+}
+
+// gitInit creates a git repository in dir holding a single commit of every file below it, without needing git
+// itself.  It writes loose objects, a branch, and an index matching the commit, so the working tree is clean.
+func gitInit(afs afero.Fs, dir string, sig GitSignature, message string) (commit string, err error) {
+	gitDir := filepath.Join(dir, ".git")
+
+	exists, err := afero.DirExists(afs, gitDir)
+	if err != nil {
+		return commit, fmt.Errorf("failed to check for %s: %w", gitDir, err)
+	}
+	if exists {
+		return commit, fmt.Errorf("%s already exists", gitDir)
+	}
+
+	for _, d := range []string{"objects/info", "objects/pack", "refs/heads", "refs/tags", "hooks", "info"} {
+		err = afs.MkdirAll(filepath.Join(gitDir, d), 0755)
+		if err != nil {
+			return commit, fmt.Errorf("failed to create %s: %w", d, err)
+		}
+	}
+
+	var entries []gitEntry
+	err = afero.Walk(afs, dir, func(p string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		if info.IsDir() {
+			if p == gitDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		data, readErr := afero.ReadFile(afs, p)
+		if readErr != nil {
+			return readErr
+		}
+
+		id, writeErr := writeGitObject(afs, gitDir, "blob", data)
+		if writeErr != nil {
+			return writeErr
+		}
+
+		rel, relErr := filepath.Rel(dir, p)
+		if relErr != nil {
+			return relErr
+		}
+
+		mode := uint32(0100644)
+		if info.Mode().Perm()&0111 != 0 {
+			mode = 0100755
+		}
+
+		entries = append(entries, gitEntry{path: filepath.ToSlash(rel), mode: mode, id: id, info: info})
+		return nil
+	})
+	if err != nil {
+		return commit, fmt.Errorf("failed to add files to git: %w", err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].path < entries[j].path })
+
+	tree, err := writeGitTree(afs, gitDir, "", entries)
+	if err != nil {
+		return commit, err
+	}
+
+	stamp := fmt.Sprintf("%d %s", sig.When.Unix(), sig.When.Format("-0700"))
+	person := fmt.Sprintf("%s <%s> %s", sig.Name, sig.Email, stamp)
+	body := fmt.Sprintf("tree %s\nauthor %s\ncommitter %s\n\n%s\n", hex.EncodeToString(tree[:]), person, person, strings.TrimSpace(message))
+
+	id, err := writeGitObject(afs, gitDir, "commit", []byte(body))
+	if err != nil {
+		return commit, err
+	}
+	commit = hex.EncodeToString(id[:])
+
+	for name, data := range map[string]string{
+		"HEAD":                           "ref: refs/heads/" + gitDefaultBranch + "\n",
+		"refs/heads/" + gitDefaultBranch: commit + "\n",
+		"config":                         "[core]\n\trepositoryformatversion = 0\n\tfilemode = true\n\tbare = false\n\tlogallrefupdates = true\n",
+		"description":                    "Unnamed repository; edit this file 'description' to name the repository.\n",
+	} {
+		err = afero.WriteFile(afs, filepath.Join(gitDir, name), []byte(data), 0644)
+		if err != nil {
+			return commit, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	err = afero.WriteFile(afs, filepath.Join(gitDir, "index"), gitIndex(entries), 0644)
+	if err != nil {
+		return commit, fmt.Errorf("failed to write git index: %w", err)
+	}
+
+	return commit, err
+}
+
+// writeGitObject stores a loose object and returns its id.
+func writeGitObject(afs afero.Fs, gitDir, kind string, data []byte) (id [sha1.Size]byte, err error) {
+	raw := append([]byte(fmt.Sprintf("%s %d\x00", kind, len(data))), data...)
+	id = sha1.Sum(raw) //nolint:gosec // git object ids are sha1
+
+	name := hex.EncodeToString(id[:])
+	objPath := filepath.Join(gitDir, "objects", name[:2], name[2:])
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	_, err = zw.Write(raw)
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		return id, fmt.Errorf("failed to compress git object: %w", err)
+	}
+
+	err = afs.MkdirAll(filepath.Dir(objPath), 0755)
+	if err != nil {
+		return id, fmt.Errorf("failed to create git object dir: %w", err)
+	}
+
+	err = afero.WriteFile(afs, objPath, buf.Bytes(), 0444)
+	if err != nil {
+		return id, fmt.Errorf("failed to write git object: %w", err)
+	}
+
+	return id, err
+}
+
+// writeGitTree stores the tree for the directory prefix, and those below it, from entries sorted by path.
+func writeGitTree(afs afero.Fs, gitDir, prefix string, entries []gitEntry) (id [sha1.Size]byte, err error) {
+	type treeEntry struct {
+		name string
+		mode string
+		id   [sha1.Size]byte
+	}
+
+	var items []treeEntry
+	for i := 0; i < len(entries); {
+		rel := strings.TrimPrefix(entries[i].path, prefix)
+		name, _, isDir := strings.Cut(rel, "/")
+		if !isDir {
+			items = append(items, treeEntry{name: name, mode: fmt.Sprintf("%o", entries[i].mode), id: entries[i].id})
+			i++
+			continue
+		}
+
+		sub := prefix + name + "/"
+		j := i
+		for j < len(entries) && strings.HasPrefix(entries[j].path, sub) {
+			j++
+		}
+
+		subID, subErr := writeGitTree(afs, gitDir, sub, entries[i:j])
+		if subErr != nil {
+			return id, subErr
+		}
+		items = append(items, treeEntry{name: name + "/", mode: "40000", id: subID})
+		i = j
+	}
+
+	// Git orders tree entries by name, with directories compared as if they ended in a slash.
+	sort.Slice(items, func(a, b int) bool { return items[a].name < items[b].name })
+
+	var buf bytes.Buffer
+	for _, it := range items {
+		fmt.Fprintf(&buf, "%s %s\x00", it.mode, strings.TrimSuffix(it.name, "/"))
+		buf.Write(it.id[:])
+	}
+
+	return writeGitObject(afs, gitDir, "tree", buf.Bytes())
+}
+
+// gitIndex builds a version 2 index holding entries, sorted by path.
+func gitIndex(entries []gitEntry) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("DIRC")
+	_ = binary.Write(&buf, binary.BigEndian, []uint32{2, uint32(len(entries))})
+
+	for _, e := range entries {
+		mtime := uint32(e.info.ModTime().Unix())
+		nsec := uint32(e.info.ModTime().Nanosecond())
+		// ctime, mtime, dev, ino, mode, uid, gid and size.  The missing stat fields just make git rehash the file.
+		_ = binary.Write(&buf, binary.BigEndian, []uint32{mtime, nsec, mtime, nsec, 0, 0, e.mode, 0, 0, uint32(e.info.Size())})
+		buf.Write(e.id[:])
+
+		flags := len(e.path)
+		if flags > 0xFFF {
+			flags = 0xFFF
+		}
+		_ = binary.Write(&buf, binary.BigEndian, uint16(flags))
+		buf.WriteString(e.path)
+
+		// Entries are NUL padded to a multiple of 8 bytes, with at least one NUL.
+		entryLen := 62 + len(e.path)
+		buf.Write(make([]byte, 8-entryLen%8))
+	}
+
+	sum := sha1.Sum(buf.Bytes()) //nolint:gosec // git index checksum is sha1
+	buf.Write(sum[:])
+
+	return buf.Bytes()
+}
+
+// insideGitRepo reports whether dir, or a directory above it, is already a git repository.
+func insideGitRepo(afs afero.Fs, dir string) bool {
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		if ok, _ := afero.Exists(afs, filepath.Join(d, ".git")); ok {
+			return true
+		}
+		if d == filepath.Dir(d) {
+			return false
+		}
+	}
+}
@@ -0,0 +1,264 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+)
+
+// GoPatch edits a Go file of an existing project at a known anchor point, working on its syntax tree rather than its
+// text, so it isn't thrown by formatting or comments, and recognises when the edit has already been made.
+type GoPatch struct {
+	// File is the path of the Go file relative to the project root.  It may be a template, e.g.
+	// pkg/{{.ProjectPackageName}}/server.go.
+	File string
+
+	// Desc says what the patch does, for reports.
+	Desc string
+
+	// Edit changes the file's syntax tree in place.  changed is false if the edit was already there.
+	Edit func(file *ast.File) (changed bool, err error)
+}
+
+// Apply parses src, edits it and returns the result formatted.  changed is false, and out is src, if there was
+// nothing to do.
+func (p GoPatch) Apply(filename string, src []byte) (out []byte, changed bool, err error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return src, false, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	changed, err = p.Edit(file)
+	if err != nil {
+		return src, false, fmt.Errorf("failed to %s in %s: %w", p.Desc, filename, err)
+	}
+	if !changed {
+		return src, false, nil
+	}
+
+	var buf bytes.Buffer
+	err = format.Node(&buf, fset, file)
+	if err != nil {
+		return src, false, fmt.Errorf("failed to print %s: %w", filename, err)
+	}
+
+	// Inserted nodes all sit at a single position, so a second pass settles their formatting.
+	out, err = format.Source(buf.Bytes())
+	if err != nil {
+		return src, false, fmt.Errorf("failed to format %s: %w", filename, err)
+	}
+
+	return out, true, nil
+}
+
+// AddSubcommandPatch registers a cobra command with its parent, e.g. rootCmd.AddCommand(metricsCmd), at the end of
+// the file's init func.  An init func is added if the file has none.
+func AddSubcommandPatch(file, parent, child string) GoPatch {
+	return GoPatch{
+		File: file,
+		Desc: fmt.Sprintf("add %s to %s", child, parent),
+		Edit: func(f *ast.File) (changed bool, err error) {
+			if findCall(f, parent, "AddCommand", func(call *ast.CallExpr) bool {
+				for _, arg := range call.Args {
+					if id, ok := arg.(*ast.Ident); ok && id.Name == child {
+						return true
+					}
+				}
+				return false
+			}) {
+				return false, nil
+			}
+
+			stmt := &ast.ExprStmt{X: &ast.CallExpr{
+				Fun:  &ast.SelectorExpr{X: ast.NewIdent(parent), Sel: ast.NewIdent("AddCommand")},
+				Args: []ast.Expr{ast.NewIdent(child)},
+			}}
+
+			var initFn *ast.FuncDecl
+			for _, decl := range f.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "init" {
+					initFn = fn
+					break
+				}
+			}
+			if initFn == nil {
+				initFn = &ast.FuncDecl{
+					Name: ast.NewIdent("init"),
+					Type: &ast.FuncType{Params: &ast.FieldList{}},
+					Body: &ast.BlockStmt{List: []ast.Stmt{stmt}},
+				}
+				placeAt(initFn, f.FileEnd)
+				f.Decls = append(f.Decls, initFn)
+				return true, nil
+			}
+
+			pos := initFn.Body.Lbrace + 1
+			if n := len(initFn.Body.List); n > 0 {
+				pos = initFn.Body.List[n-1].End()
+			}
+			placeAt(stmt, pos)
+			initFn.Body.List = append(initFn.Body.List, stmt)
+
+			return true, nil
+		},
+	}
+}
+
+// AddRoutePatch registers handler, a Go expression, for pattern on an http.ServeMux, just after the last route
+// already registered on mux in function fn, e.g. mux.HandleFunc("/whoami", s.whoamiHandler) in NewServer.
+func AddRoutePatch(file, fn, mux, pattern, handler string) GoPatch {
+	return GoPatch{
+		File: file,
+		Desc: fmt.Sprintf("add route %s to %s", pattern, fn),
+		Edit: func(f *ast.File) (changed bool, err error) {
+			handlerExpr, err := parser.ParseExpr(handler)
+			if err != nil {
+				return false, fmt.Errorf("bad handler %q: %w", handler, err)
+			}
+
+			var body *ast.BlockStmt
+			for _, decl := range f.Decls {
+				if d, ok := decl.(*ast.FuncDecl); ok && d.Name.Name == fn && d.Body != nil {
+					body = d.Body
+					break
+				}
+			}
+			if body == nil {
+				return false, fmt.Errorf("no func %s", fn)
+			}
+
+			quoted := strconv.Quote(pattern)
+			last := -1
+			for i, stmt := range body.List {
+				call, ok := routeCall(stmt, mux)
+				if !ok {
+					continue
+				}
+				if lit, isLit := call.Args[0].(*ast.BasicLit); isLit && lit.Value == quoted {
+					return false, nil
+				}
+				last = i
+			}
+			if last < 0 {
+				return false, fmt.Errorf("no %s.Handle or %s.HandleFunc call in %s to add the route after", mux, mux, fn)
+			}
+
+			stmt := &ast.ExprStmt{X: &ast.CallExpr{
+				Fun:  &ast.SelectorExpr{X: ast.NewIdent(mux), Sel: ast.NewIdent("HandleFunc")},
+				Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: quoted}, handlerExpr},
+			}}
+			placeAt(stmt, body.List[last].End())
+			body.List = append(body.List[:last+1], append([]ast.Stmt{stmt}, body.List[last+1:]...)...)
+
+			return true, nil
+		},
+	}
+}
+
+// routeCall returns stmt's call if it registers a route on mux.
+func routeCall(stmt ast.Stmt, mux string) (call *ast.CallExpr, ok bool) {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return nil, false
+	}
+
+	call, ok = expr.X.(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 {
+		return nil, false
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (sel.Sel.Name != "Handle" && sel.Sel.Name != "HandleFunc") {
+		return nil, false
+	}
+
+	id, ok := sel.X.(*ast.Ident)
+	if !ok || id.Name != mux {
+		return nil, false
+	}
+
+	return call, true
+}
+
+// findCall reports whether the file calls recv.method anywhere with arguments that match.
+func findCall(f *ast.File, recv, method string, match func(call *ast.CallExpr) bool) (found bool) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != method {
+			return true
+		}
+
+		if id, isIdent := sel.X.(*ast.Ident); isIdent && id.Name == recv && match(call) {
+			found = true
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// presenceMarkers are the positions placeAt leaves unset, as setting them adds a token.
+var presenceMarkers = map[string]bool{ //nolint:gochecknoglobals // lookup table
+	"CallExpr.Ellipsis": true,
+	"GenDecl.Lparen":    true,
+	"GenDecl.Rparen":    true,
+}
+
+// placeAt moves every position in a new node to pos, so that when it is grafted into a file's tree the printer puts
+// it, and the comments around it, in the right order.  Nodes parsed on their own would otherwise carry positions
+// that mean nothing in that file.
+func placeAt(node ast.Node, pos token.Pos) {
+	posType := reflect.TypeOf(token.NoPos)
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+
+		v := reflect.ValueOf(n)
+		if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+			return true
+		}
+
+		v = v.Elem()
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if field.Type() != posType || !field.CanSet() {
+				continue
+			}
+
+			// Some positions only record whether a token is there at all, like the ... of a variadic call.
+			if field.Int() == int64(token.NoPos) && presenceMarkers[v.Type().Name()+"."+v.Type().Field(i).Name] {
+				continue
+			}
+
+			field.SetInt(int64(pos))
+		}
+
+		return true
+	})
+}
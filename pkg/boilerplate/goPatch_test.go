@@ -0,0 +1,112 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rootSrc = `package cmd
+
+func init() {
+	// Flags go here.
+	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
+
+// initConfig reads in config file and ENV variables if set.
+func initConfig() {}
+`
+
+const serverSrc = `package svc
+
+func NewServer() (s *Server) {
+	mux := http.NewServeMux()
+
+	// Register routes
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", s.healthzHandler)
+
+	return s
+}
+
+// Start starts the server.
+func (s *Server) Start() {}
+`
+
+func TestAddSubcommandPatch(t *testing.T) {
+	p := AddSubcommandPatch("cmd/root.go", "rootCmd", "metricsCmd")
+
+	out, changed, err := p.Apply("root.go", []byte(rootSrc))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, `package cmd
+
+func init() {
+	// Flags go here.
+	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.AddCommand(metricsCmd)
+}
+
+// initConfig reads in config file and ENV variables if set.
+func initConfig() {}
+`, string(out))
+
+	_, changed, err = p.Apply("root.go", out)
+	require.NoError(t, err)
+	assert.False(t, changed, "subcommand added twice")
+
+	out, changed, err = p.Apply("other.go", []byte("package cmd\n\nvar x = 1\n"))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Contains(t, string(out), "func init() { rootCmd.AddCommand(metricsCmd) }")
+
+	_, _, err = p.Apply("bad.go", []byte("package cmd\n\nfunc {"))
+	assert.ErrorContains(t, err, "failed to parse bad.go")
+}
+
+func TestAddRoutePatch(t *testing.T) {
+	p := AddRoutePatch("server.go", "NewServer", "mux", "/whoami", `s.requireAuth(s.whoamiHandler)`)
+
+	out, changed, err := p.Apply("server.go", []byte(serverSrc))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, `package svc
+
+func NewServer() (s *Server) {
+	mux := http.NewServeMux()
+
+	// Register routes
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", s.healthzHandler)
+	mux.HandleFunc("/whoami", s.requireAuth(s.whoamiHandler))
+
+	return s
+}
+
+// Start starts the server.
+func (s *Server) Start() {}
+`, string(out))
+
+	_, changed, err = p.Apply("server.go", out)
+	require.NoError(t, err)
+	assert.False(t, changed, "route added twice")
+
+	_, _, err = AddRoutePatch("server.go", "Missing", "mux", "/x", "h").Apply("server.go", []byte(serverSrc))
+	assert.ErrorContains(t, err, "no func Missing")
+
+	_, _, err = AddRoutePatch("server.go", "NewServer", "router", "/x", "h").Apply("server.go", []byte(serverSrc))
+	assert.ErrorContains(t, err, "no router.Handle or router.HandleFunc call in NewServer")
+
+	_, _, err = AddRoutePatch("server.go", "NewServer", "mux", "/x", "h(").Apply("server.go", []byte(serverSrc))
+	assert.ErrorContains(t, err, "bad handler")
+}
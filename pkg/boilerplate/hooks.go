@@ -0,0 +1,287 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"go/format"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Names of the built-in hooks.
+const (
+	HookGofmt     = "gofmt"
+	HookGoModTidy = "go-mod-tidy"
+	HookGitInit   = "git-init"
+	HookPreCommit = "pre-commit"
+)
+
+// ErrHookSkipped is returned, wrapped with the reason, by a hook that has nothing to do or can't run here.
+var ErrHookSkipped = errors.New("skipped")
+
+// ErrCommandHooksNotAllowed is returned, wrapping ErrHookSkipped, for a command hook that wasn't allowed to run.
+var ErrCommandHooksNotAllowed = fmt.Errorf("%w: command hooks are not allowed", ErrHookSkipped)
+
+// HookStatus is the outcome of running a hook.
+type HookStatus string
+
+const (
+	HookOK      HookStatus = "ok"
+	HookSkipped HookStatus = "skipped"
+	HookFailed  HookStatus = "failed"
+)
+
+// HookResult reports how a single hook went.  Err holds the reason for a skip or failure.
+type HookResult struct {
+	Name   string
+	Status HookStatus
+	Err    error
+}
+
+// DefaultHooks are the hooks the built-in project types run: format the Go code, tidy the module, commit the result
+// to a new git repository, and install the project's pre-commit hook.
+func DefaultHooks() []Hook {
+	return []Hook{GofmtHook(), GoModTidyHook(), GitInitHook(), PreCommitHook()}
+}
+
+// BuiltinHook returns the built-in hook with the given name.
+func BuiltinHook(name string) (hook Hook, ok bool) {
+	for _, h := range DefaultHooks() {
+		if h.Name == name {
+			return h, true
+		}
+	}
+
+	return hook, false
+}
+
+// GofmtHook formats every .go file in the project with go/format.
+func GofmtHook() Hook {
+	return Hook{
+		Name: HookGofmt,
+		Run: func(ctx context.Context, outFs afero.Fs, projDir string, vals map[string]any) error {
+			var failed []string
+			formatted := 0
+
+			err := afero.Walk(outFs, projDir, func(path string, info os.FileInfo, walkErr error) error {
+				if walkErr != nil {
+					return walkErr
+				}
+				// The manifest's base keeps the files as they were rendered.
+				if info.IsDir() && (info.Name() == ".git" || path == filepath.Join(projDir, BaseDir)) {
+					return filepath.SkipDir
+				}
+				if info.IsDir() || filepath.Ext(path) != ".go" {
+					return nil
+				}
+
+				src, readErr := afero.ReadFile(outFs, path)
+				if readErr != nil {
+					return readErr
+				}
+				formatted++
+
+				out, fmtErr := format.Source(src)
+				if fmtErr != nil {
+					failed = append(failed, fmt.Sprintf("%s: %v", path, fmtErr))
+					return nil
+				}
+				if bytes.Equal(src, out) {
+					return nil
+				}
+
+				return afero.WriteFile(outFs, path, out, info.Mode().Perm())
+			})
+			if err != nil {
+				return fmt.Errorf("failed to format Go files: %w", err)
+			}
+
+			if len(failed) > 0 {
+				return fmt.Errorf("failed to format:\n\t%s", strings.Join(failed, "\n\t"))
+			}
+			if formatted == 0 {
+				return fmt.Errorf("%w: no Go files", ErrHookSkipped)
+			}
+
+			return nil
+		},
+	}
+}
+
+// GoModTidyHook runs go mod tidy in the project.  It is skipped if go isn't installed.
+func GoModTidyHook() Hook {
+	return Hook{
+		Name: HookGoModTidy,
+		Run: func(ctx context.Context, outFs afero.Fs, projDir string, vals map[string]any) error {
+			if ok, _ := afero.Exists(outFs, filepath.Join(projDir, "go.mod")); !ok {
+				return fmt.Errorf("%w: no go.mod", ErrHookSkipped)
+			}
+
+			return runCommand(ctx, outFs, projDir, "go", "mod", "tidy")
+		},
+	}
+}
+
+// GitInitHook creates a git repository holding the generated project as its first commit, without needing git
+// installed.  It is skipped if the project is already inside a repository.
+func GitInitHook() Hook {
+	return Hook{
+		Name: HookGitInit,
+		Run: func(ctx context.Context, outFs afero.Fs, projDir string, vals map[string]any) error {
+			if insideGitRepo(outFs, projDir) {
+				return fmt.Errorf("%w: already inside a git repository", ErrHookSkipped)
+			}
+
+			sig := GitSignature{
+				Name:  firstValue(vals, "boilerplate", "MaintainerName", "ProjectMaintainerName", "OwnerName"),
+				Email: firstValue(vals, "boilerplate@localhost", "MaintainerEmail", "ProjectMaintainerEmail", "OwnerEmail"),
+				When:  nowFunc(),
+			}
+
+			_, err := gitInit(outFs, projDir, sig, "Initial commit from boilerplate")
+			return err
+		},
+	}
+}
+
+// PreCommitHook installs the project's pre-commit-hook.sh as the git pre-commit hook.  The script itself is generated
+// executable by a file rule.
+func PreCommitHook() Hook {
+	return Hook{
+		Name: HookPreCommit,
+		Run: func(ctx context.Context, outFs afero.Fs, projDir string, vals map[string]any) error {
+			script := filepath.Join(projDir, "pre-commit-hook.sh")
+			if ok, _ := afero.Exists(outFs, script); !ok {
+				return fmt.Errorf("%w: no pre-commit-hook.sh", ErrHookSkipped)
+			}
+
+			hooksDir := filepath.Join(projDir, ".git", "hooks")
+			if ok, _ := afero.DirExists(outFs, hooksDir); !ok {
+				return fmt.Errorf("%w: not a git repository", ErrHookSkipped)
+			}
+
+			// Hooks run from the top of the work tree, so this keeps following the project's copy of the script.
+			hook := filepath.Join(hooksDir, "pre-commit")
+			err := afero.WriteFile(outFs, hook, []byte("#!/bin/sh\nexec ./pre-commit-hook.sh \"$@\"\n"), 0755)
+			if err != nil {
+				return fmt.Errorf("failed to install pre-commit hook: %w", err)
+			}
+
+			return outFs.Chmod(hook, 0755)
+		},
+	}
+}
+
+// CommandHook runs a shell command in the project directory.  The command is a template over the values, e.g.
+// "make generate NAME={{.ProjectName}}".  Generate only runs command hooks if they are allowed.
+func CommandHook(name, command string) Hook {
+	return Hook{
+		Name:    name,
+		Command: command,
+		Run: func(ctx context.Context, outFs afero.Fs, projDir string, vals map[string]any) error {
+			rendered, err := renderCommand(name, command, vals)
+			if err != nil {
+				return err
+			}
+
+			return runCommand(ctx, outFs, projDir, "sh", "-c", rendered)
+		},
+	}
+}
+
+// renderCommand renders a command hook's command over the values.
+func renderCommand(name, command string, vals map[string]any) (rendered string, err error) {
+	tmpl, err := newTemplate(name).Parse(command)
+	if err != nil {
+		return rendered, fmt.Errorf("bad command: %w", err)
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, vals)
+	if err != nil {
+		return rendered, fmt.Errorf("failed to render command: %w", err)
+	}
+
+	return buf.String(), err
+}
+
+// guardCommandHooks returns hooks with their command hooks made safe to run: unless allow is set, they are skipped
+// without running anything, and if it is, each one's command is written to log before it runs.
+func guardCommandHooks(hooks []Hook, allow bool, log io.Writer) []Hook {
+	guarded := make([]Hook, 0, len(hooks))
+	for _, h := range hooks {
+		if h.Command == "" {
+			guarded = append(guarded, h)
+			continue
+		}
+
+		run, name, command := h.Run, h.Name, h.Command
+		if !allow {
+			h.Run = func(context.Context, afero.Fs, string, map[string]any) error {
+				return fmt.Errorf("%w, so %q was not run", ErrCommandHooksNotAllowed, command)
+			}
+		} else {
+			h.Run = func(ctx context.Context, outFs afero.Fs, projDir string, vals map[string]any) error {
+				rendered, err := renderCommand(name, command, vals)
+				if err != nil {
+					return err
+				}
+
+				if log != nil {
+					fmt.Fprintf(log, "hook %s runs: %s\n", name, rendered)
+				}
+
+				return run(ctx, outFs, projDir, vals)
+			}
+		}
+		guarded = append(guarded, h)
+	}
+
+	return guarded
+}
+
+// runCommand runs an external program in projDir, which has to be on the real file system.
+func runCommand(ctx context.Context, outFs afero.Fs, projDir string, name string, args ...string) error {
+	if _, ok := outFs.(*afero.OsFs); !ok {
+		return fmt.Errorf("%w: %s needs the project on disk", ErrHookSkipped, name)
+	}
+
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("%w: %s is not installed", ErrHookSkipped, name)
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = projDir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %w\n%s", name, strings.Join(args, " "), err, bytes.TrimSpace(out))
+	}
+
+	return nil
+}
+
+// firstValue returns the first of keys with a non-empty value, or def.
+func firstValue(vals map[string]any, def string, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := vals[k].(string); ok && s != "" {
+			return s
+		}
+	}
+
+	return def
+}
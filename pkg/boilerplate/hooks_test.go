@@ -0,0 +1,120 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGofmtHook(t *testing.T) {
+	afs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(afs, "/proj/main.go", []byte("package main\nfunc main(){\n}\n"), 0644))
+	require.NoError(t, afero.WriteFile(afs, "/proj/README.md", []byte("not go\n"), 0644))
+
+	require.NoError(t, GofmtHook().Run(context.Background(), afs, "/proj", nil))
+
+	data, err := afero.ReadFile(afs, "/proj/main.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\nfunc main() {\n}\n", string(data))
+
+	require.NoError(t, afero.WriteFile(afs, "/proj/broken.go", []byte("package main\nfunc {\n"), 0644))
+	err = GofmtHook().Run(context.Background(), afs, "/proj", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "/proj/broken.go")
+
+	require.NoError(t, afs.MkdirAll("/empty", 0755))
+	err = GofmtHook().Run(context.Background(), afs, "/empty", nil)
+	assert.ErrorIs(t, err, ErrHookSkipped)
+}
+
+func TestGitInitHook(t *testing.T) {
+	dir := t.TempDir()
+	afs := afero.NewOsFs()
+	require.NoError(t, afero.WriteFile(afs, dir+"/README.md", []byte("# proj\n"), 0644))
+	require.NoError(t, afs.MkdirAll(dir+"/cmd", 0755))
+	require.NoError(t, afero.WriteFile(afs, dir+"/cmd/root.go", []byte("package cmd\n"), 0644))
+	require.NoError(t, afero.WriteFile(afs, dir+"/pre-commit-hook.sh", []byte("#!/bin/sh\n"), 0755))
+
+	nowFunc = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	defer func() { nowFunc = time.Now }()
+
+	vals := map[string]any{"MaintainerName": "Tester", "MaintainerEmail": "tester@example.com"}
+	require.NoError(t, GitInitHook().Run(context.Background(), afs, dir, vals))
+	require.NoError(t, PreCommitHook().Run(context.Background(), afs, dir, vals))
+
+	// The project is now a repository, so a second run has nothing to do.
+	assert.ErrorIs(t, GitInitHook().Run(context.Background(), afs, dir, vals), ErrHookSkipped)
+
+	info, err := afs.Stat(dir + "/.git/hooks/pre-commit")
+	require.NoError(t, err)
+	assert.Equal(t, 0755, int(info.Mode().Perm()))
+
+	if _, lookErr := exec.LookPath("git"); lookErr != nil {
+		t.Skip("git not installed")
+	}
+
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, gitErr := cmd.CombinedOutput()
+		require.NoError(t, gitErr, "git %v: %s", args, out)
+		return strings.TrimSpace(string(out))
+	}
+
+	git("fsck", "--strict")
+	assert.Equal(t, "", git("status", "--porcelain"), "expected a clean work tree")
+	assert.Equal(t, "Tester <tester@example.com> Initial commit from boilerplate", git("log", "--format=%an <%ae> %s"))
+	assert.Equal(t, "main", git("rev-parse", "--abbrev-ref", "HEAD"))
+	assert.Equal(t, "README.md\ncmd/root.go\npre-commit-hook.sh", git("ls-files"))
+	assert.Contains(t, git("ls-files", "-s", "pre-commit-hook.sh"), "100755")
+}
+
+func TestPreCommitHook_NoRepo(t *testing.T) {
+	afs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(afs, "/proj/pre-commit-hook.sh", []byte("#!/bin/sh\n"), 0644))
+
+	assert.ErrorIs(t, PreCommitHook().Run(context.Background(), afs, "/proj", nil), ErrHookSkipped)
+	assert.ErrorIs(t, PreCommitHook().Run(context.Background(), afs, "/other", nil), ErrHookSkipped)
+	assert.ErrorIs(t, GoModTidyHook().Run(context.Background(), afs, "/proj", nil), ErrHookSkipped)
+}
+
+func TestGuardCommandHooks(t *testing.T) {
+	dir := t.TempDir()
+	afs := afero.NewOsFs()
+	vals := map[string]any{"ProjectName": "proj"}
+	hooks := []Hook{GofmtHook(), CommandHook("touch", "touch {{.ProjectName}}.touched")}
+
+	guarded := guardCommandHooks(hooks, false, nil)
+	require.Len(t, guarded, 2)
+	assert.Equal(t, "gofmt", guarded[0].Name)
+	err := guarded[1].Run(context.Background(), afs, dir, vals)
+	assert.ErrorIs(t, err, ErrCommandHooksNotAllowed)
+	assert.ErrorIs(t, err, ErrHookSkipped)
+	exists, err := afero.Exists(afs, dir+"/proj.touched")
+	require.NoError(t, err)
+	assert.False(t, exists, "a command hook ran without being allowed")
+
+	var log strings.Builder
+	guarded = guardCommandHooks(hooks, true, &log)
+	require.NoError(t, guarded[1].Run(context.Background(), afs, dir, vals))
+	assert.Equal(t, "hook touch runs: touch proj.touched\n", log.String())
+	exists, err = afero.Exists(afs, dir+"/proj.touched")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
@@ -82,13 +82,15 @@ func GetIndirectSelectionParamsPromptMessaging() map[ParamPrompt]Prompt {
 
 	// Add indirect selection specific prompts
 	prompts[ProjEnvPrefix] = Prompt{
+		Order:        prompts[ProjEnvPrefix].Order,
 		PromptMsg:    "Enter environment variable prefix for your indirect selection service.",
 		InputFailMsg: "failed to read environment prefix",
 		Validations:  envPrefix,
-		DefaultValue: "SERVICE",
+		DefaultValue: "{{screamingSnake .ProjectName}}",
 	}
 
 	prompts[ServerDefPort] = Prompt{
+		Order:        prompts[ServerDefPort].Order,
 		PromptMsg:    "Enter default gRPC port.",
 		InputFailMsg: "failed to read default gRPC port",
 		Validations:  portValidation,
@@ -96,16 +98,18 @@ func GetIndirectSelectionParamsPromptMessaging() map[ParamPrompt]Prompt {
 	}
 
 	prompts[OwnerName] = Prompt{
+		Order:        prompts[OwnerName].Order,
 		PromptMsg:    "Enter the owner/organization name.",
 		InputFailMsg: "failed to read owner name",
-		DefaultValue: "you@example.com",
+		DefaultValue: "{{.MaintainerName}}",
 	}
 
 	prompts[OwnerEmail] = Prompt{
+		Order:        prompts[OwnerEmail].Order,
 		PromptMsg:    "Enter the owner/organization email address.",
 		InputFailMsg: "failed to read owner email address",
 		Validations:  emailValidation,
-		DefaultValue: "code@example.com",
+		DefaultValue: "{{.MaintainerEmail}}",
 	}
 
 	return prompts
@@ -113,18 +117,5 @@ func GetIndirectSelectionParamsPromptMessaging() map[ParamPrompt]Prompt {
 
 func IndirectSelectionParamsFromPrompts(params *IndirectSelectionParams, r io.Reader) (err error) {
 	prompts := GetIndirectSelectionParamsPromptMessaging()
-	err = paramsFromPrompts(r, prompts, params)
-	if err != nil {
-		return err
-	}
-
-	// Auto-populate server descriptions from project descriptions
-	if params.ServerShortDesc == "" {
-		params.ServerShortDesc = params.ProjectShortDesc
-	}
-	if params.ServerLongDesc == "" {
-		params.ServerLongDesc = params.ProjectLongDesc
-	}
-
-	return err
+	return paramsFromPrompts(r, prompts, params)
 }
@@ -0,0 +1,328 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// TemplateLint is what LintTemplate found in a template tree.
+type TemplateLint struct {
+	// Vars lists the values each file uses, in its path or its contents, keyed by its path in the template tree.
+	Vars map[string][]string `json:"vars"`
+
+	// Problems lists templates that don't parse, values no question or derived value provides, questions nothing
+	// uses, and generated imports the generated go.mod files don't require.
+	Problems []VerifyProblem `json:"problems,omitempty"`
+}
+
+// varUses maps the values a template uses to the line each is first used on.
+type varUses map[string]int
+
+// LintTemplate checks a project type's template tree without generating anything.  Every path and file is parsed to
+// find the values it uses, which are checked against those the type provides.  The project is then rendered in
+// memory with each question's default, or <Key> where there is none, and the imports in the generated Go files are
+// checked against the generated go.mod files.  Problems are reported in TemplateLint.Problems; err is only for
+// trees that can't be read.
+func LintTemplate(pt ProjectType) (lint TemplateLint, err error) {
+	lint.Vars = make(map[string][]string)
+
+	vals, asked, err := pt.sampleValues(lintPlaceholder, nil)
+	if err != nil {
+		return lint, err
+	}
+
+	w, err := NewTmplWriterFromFs(afero.NewMemMapFs(), pt.Fs, pt.Root, pt.Name, vals)
+	if err != nil {
+		return lint, err
+	}
+	w.FileRules = append(w.FileRules, pt.FileRules...)
+
+	used := make(map[string]bool)
+	for _, fp := range w.FilePaths {
+		rel := w.templateRel(fp.Path)
+
+		uses := make(varUses)
+		problems := uses.addTemplate(rel, rel, [2]string{}, 0)
+		if !fp.IsDir {
+			problems = append(problems, w.fileVars(fp, rel, uses)...)
+		}
+		lint.Problems = append(lint.Problems, problems...)
+
+		for _, name := range sortedKeys(uses) {
+			used[name] = true
+			lint.Vars[rel] = append(lint.Vars[rel], name)
+
+			if _, ok := vals[name]; !ok {
+				lint.Problems = append(lint.Problems, VerifyProblem{File: rel, Line: uses[name], Msg: fmt.Sprintf("uses .%s, which the %s type doesn't provide", name, pt.Name)})
+			}
+		}
+	}
+
+	// Values used to decide which files are generated count too.
+	conditions := make([]string, 0, len(w.FileRules))
+	for _, r := range w.FileRules {
+		conditions = append(conditions, whenTemplate(r.When))
+	}
+	usedBy(conditions, used)
+
+	for _, p := range asked {
+		// Hidden prompts are never asked, only derived, so nothing is lost if no template uses them.
+		if p.Hidden {
+			continue
+		}
+
+		var affected []string
+		affected, err = pt.affectedValues(ParamPrompt(p.Key), vals)
+		if err != nil {
+			return lint, err
+		}
+
+		isUsed := false
+		for _, name := range affected {
+			isUsed = isUsed || used[name]
+		}
+
+		if !isUsed {
+			lint.Problems = append(lint.Problems, VerifyProblem{Msg: fmt.Sprintf("%s asks for %s, but nothing uses it", pt.Name, p.Key)})
+		}
+	}
+
+	// Rendering can only fail in ways already reported, so it is only worth doing on a clean tree.
+	if len(lint.Problems) == 0 {
+		lint.Problems = append(lint.Problems, w.lintRendered()...)
+	}
+
+	sort.SliceStable(lint.Problems, func(i, j int) bool {
+		if lint.Problems[i].File != lint.Problems[j].File {
+			return lint.Problems[i].File < lint.Problems[j].File
+		}
+		return lint.Problems[i].Line < lint.Problems[j].Line
+	})
+
+	return lint, err
+}
+
+// lintPlaceholder answers questions without defaults with something usable as a Go identifier.
+func lintPlaceholder(key ParamPrompt) string {
+	return strings.ToLower(string(key))
+}
+
+// affectedValues lists the template values that change with the answer to a question.  That covers values renamed
+// or derived from it, and other questions' defaults.
+func (pt ProjectType) affectedValues(key ParamPrompt, vals map[string]any) (affected []string, err error) {
+	changed, _, err := pt.sampleValues(lintPlaceholder, map[ParamPrompt]string{key: lintPlaceholder(key) + "x"})
+	if err != nil {
+		return affected, err
+	}
+
+	for _, name := range sortedKeys(changed) {
+		if fmt.Sprint(changed[name]) != fmt.Sprint(vals[name]) {
+			affected = append(affected, name)
+		}
+	}
+
+	return affected, err
+}
+
+// templateRel returns a path in the template tree relative to its root.
+func (w TmplWriter) templateRel(p string) string {
+	if w.ProjDir == "." {
+		return p
+	}
+
+	return strings.TrimPrefix(p, w.ProjDir+"/")
+}
+
+// fileVars adds the values a template file uses.  Binary and verbatim files use none.
+func (w TmplWriter) fileVars(fp FilePath, rel string, uses varUses) (problems []VerifyProblem) {
+	verbatim, err := w.isVerbatim(fp)
+	if err != nil {
+		return []VerifyProblem{{File: rel, Msg: err.Error()}}
+	}
+	if verbatim {
+		return problems
+	}
+
+	data, err := fs.ReadFile(w.TemplFs, fp.Path)
+	if err != nil {
+		return []VerifyProblem{{File: rel, Msg: err.Error()}}
+	}
+
+	dirs, body, found, err := parseFrontMatter(string(data))
+	if err != nil {
+		return []VerifyProblem{{File: rel, Line: 1, Msg: err.Error()}}
+	}
+	if dirs.Verbatim {
+		return problems
+	}
+
+	delims := w.Delims
+	if dirs.Delims[0] != "" {
+		delims = dirs.Delims
+	}
+
+	offset := 0
+	if found {
+		offset = 1
+	}
+
+	return uses.addTemplate(rel, body, delims, offset)
+}
+
+// addTemplate parses a template and adds the values it uses.  offset is added to line numbers, to account for a
+// front-matter directive.
+func (u varUses) addTemplate(name, text string, delims [2]string, offset int) (problems []VerifyProblem) {
+	tmpl, err := newTemplate(name).Delims(delims[0], delims[1]).Parse(text)
+	if err != nil {
+		te := newTemplateError(name, err)
+		if te.Line > 0 {
+			te.Line += offset
+		}
+		msg := regexp.MustCompile(`^template: `+regexp.QuoteMeta(name)+`:[\d:]+ `).ReplaceAllString(err.Error(), "")
+		return []VerifyProblem{{File: name, Line: te.Line, Msg: msg}}
+	}
+
+	u.addTemplateTree(tmpl, offset)
+
+	return problems
+}
+
+// addTemplateTree adds the values used by a parsed template and those it defines.
+func (u varUses) addTemplateTree(tmpl *template.Template, offset int) {
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil && t.Tree.Root != nil {
+			u.addNode(t.Tree, t.Tree.Root, true, offset)
+		}
+	}
+}
+
+// addNode walks a parse tree for fields of the template values, i.e. .Name where dot is the values themselves, and
+// $.Name anywhere.  Inside range and with, dot is something else.
+func (u varUses) addNode(tree *parse.Tree, node parse.Node, atRoot bool, offset int) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			u.addNode(tree, c, atRoot, offset)
+		}
+	case *parse.ActionNode:
+		u.addNode(tree, n.Pipe, atRoot, offset)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Cmds {
+			u.addNode(tree, c, atRoot, offset)
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			u.addNode(tree, a, atRoot, offset)
+		}
+	case *parse.IfNode:
+		u.addBranch(tree, &n.BranchNode, atRoot, atRoot, offset)
+	case *parse.RangeNode:
+		u.addBranch(tree, &n.BranchNode, atRoot, false, offset)
+	case *parse.WithNode:
+		u.addBranch(tree, &n.BranchNode, atRoot, false, offset)
+	case *parse.TemplateNode:
+		u.addNode(tree, n.Pipe, atRoot, offset)
+	case *parse.ChainNode:
+		u.addNode(tree, n.Node, atRoot, offset)
+	case *parse.FieldNode:
+		if atRoot {
+			u.add(tree, n, n.Ident[0], offset)
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			u.add(tree, n, n.Ident[1], offset)
+		}
+	}
+}
+
+func (u varUses) addBranch(tree *parse.Tree, n *parse.BranchNode, atRoot, inList bool, offset int) {
+	u.addNode(tree, n.Pipe, atRoot, offset)
+	u.addNode(tree, n.List, inList, offset)
+	u.addNode(tree, n.ElseList, atRoot, offset)
+}
+
+func (u varUses) add(tree *parse.Tree, n parse.Node, name string, offset int) {
+	if _, seen := u[name]; seen {
+		return
+	}
+
+	// The location looks like name:line:col.
+	location, _ := tree.ErrorContext(n)
+	parts := strings.Split(location, ":")
+	line := 0
+	if len(parts) >= 3 {
+		line, _ = strconv.Atoi(parts[len(parts)-2])
+	}
+	if line > 0 {
+		line += offset
+	}
+
+	u[name] = line
+}
+
+// usedBy marks the values used by some template expressions.  Expressions that don't parse are reported elsewhere.
+func usedBy(exprs []string, used map[string]bool) {
+	uses := make(varUses)
+	for _, expr := range exprs {
+		_ = uses.addTemplate("expr", expr, [2]string{}, 0)
+	}
+
+	for name := range uses {
+		used[name] = true
+	}
+}
+
+// lintRendered renders the project in memory and checks the imports of the Go files generated against the go.mod
+// files generated.  Problems are reported against the template files responsible.
+func (w TmplWriter) lintRendered() (problems []VerifyProblem) {
+	rendered, err := w.RenderProject("/")
+	if err != nil {
+		var te *TemplateError
+		if errors.As(err, &te) {
+			return []VerifyProblem{{File: w.templateRel(te.File), Line: te.Line, Msg: te.Err.Error()}}
+		}
+		return []VerifyProblem{{Msg: err.Error()}}
+	}
+
+	sources := make(map[string]string, len(w.FilePaths))
+	for _, fp := range w.FilePaths {
+		sources[path.Clean(fp.TemplPath)] = w.templateRel(fp.Path)
+	}
+
+	problems, err = checkProjectImports(rendered, "/")
+	if err != nil {
+		return []VerifyProblem{{Msg: err.Error()}}
+	}
+
+	for i, p := range problems {
+		if src, ok := sources[p.File]; ok {
+			problems[i].File = src
+		}
+	}
+
+	return problems
+}
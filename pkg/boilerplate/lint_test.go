@@ -0,0 +1,132 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lintSpec = `
+name: lambda
+prompts:
+  - key: ProjectName
+  - key: ProjectPackage
+    default: github.com/example/{{.ProjectName}}
+  - key: Runtime
+    default: go
+  - key: Memory
+    default: "128"
+  - key: Tracing
+derived:
+  - key: Handler
+    value: "{{.ProjectName}}-handler"
+files:
+  - pattern: tracing.go
+    when: .Tracing
+`
+
+func TestLintTemplate(t *testing.T) {
+	for _, tc := range []struct {
+		Name     string
+		Files    map[string]string
+		WantVars map[string][]string
+		Want     []string
+	}{
+		{
+			Name: "Unknown and unused values",
+			Files: map[string]string{
+				"{{.ProjectName}}/main.go_":   "package main\n\n// {{.Handler}} uses {{.ProjectNmae}}\n{{range .Items}}{{.Name}}{{$.Memroy}}{{end}}\n",
+				"{{.ProjectName}}/go.mod_":    "module {{.ProjectPackage}}\n",
+				"{{.ProjectName}}/README.md":  "# boilerplate: delims=[[ ]]\n\n[[.ProjectName]] {{.NotATemplate}}\n[[if .Broken\n",
+				"{{.ProjectName}}/tracing.go": "package main\n",
+			},
+			WantVars: map[string][]string{
+				"{{.ProjectName}}":            {"ProjectName"},
+				"{{.ProjectName}}/main.go_":   {"Handler", "Items", "Memroy", "ProjectName", "ProjectNmae"},
+				"{{.ProjectName}}/go.mod_":    {"ProjectName", "ProjectPackage"},
+				"{{.ProjectName}}/README.md":  {"ProjectName"},
+				"{{.ProjectName}}/tracing.go": {"ProjectName"},
+			},
+			Want: []string{
+				"lambda asks for Runtime, but nothing uses it",
+				"lambda asks for Memory, but nothing uses it",
+				"{{.ProjectName}}/README.md:5: unclosed action started at {{.ProjectName}}/README.md:3",
+				"{{.ProjectName}}/main.go_:3: uses .ProjectNmae, which the lambda type doesn't provide",
+				"{{.ProjectName}}/main.go_:4: uses .Items, which the lambda type doesn't provide",
+				"{{.ProjectName}}/main.go_:4: uses .Memroy, which the lambda type doesn't provide",
+			},
+		},
+		{
+			Name: "Missing requirement",
+			Files: map[string]string{
+				"{{.ProjectName}}/main.go_":   "package main\n\nimport \"github.com/spf13/cobra\"\n\nvar _ = cobra.Command{Use: \"{{.Handler}} {{.Runtime}} {{.Memory}}\"}\n",
+				"{{.ProjectName}}/go.mod_":    "module {{.ProjectPackage}}\n\ngo 1.22\n",
+				"{{.ProjectName}}/tracing.go": "package main\n",
+			},
+			Want: []string{
+				"{{.ProjectName}}/main.go_:3: imports github.com/spf13/cobra, which isn't in module github.com/example/projectname or any module it requires",
+			},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			templFs := fstest.MapFS{TemplateSpecFileName: {Data: []byte(lintSpec)}}
+			for name, content := range tc.Files {
+				templFs[name] = &fstest.MapFile{Data: []byte(content)}
+			}
+
+			spec, err := LoadTemplateSpec(templFs, ".")
+			require.NoError(t, err)
+
+			lint, err := LintTemplate(ProjectTypeFromSpec(spec, templFs, "."))
+			require.NoError(t, err)
+
+			for file, vars := range tc.WantVars {
+				assert.Equal(t, vars, lint.Vars[file], file)
+			}
+
+			got := make([]string, 0, len(lint.Problems))
+			for _, p := range lint.Problems {
+				got = append(got, p.String())
+			}
+			assert.Equal(t, tc.Want, got)
+		})
+	}
+}
+
+func TestLintTemplate_BuiltinTypes(t *testing.T) {
+	// Questions these types ask that their templates don't use yet.
+	unused := map[string][]string{
+		SPAProjectType:        {"GolangVersion", "DbtRepo", "MaintainerName", "MaintainerEmail"},
+		IndirectSelectionType: {"GolangVersion", "ProjectLongDesc", "OwnerName", "OwnerEmail"},
+	}
+
+	for _, pt := range ProjectTypes() {
+		t.Run(pt.Name, func(t *testing.T) {
+			lint, err := LintTemplate(pt)
+			require.NoError(t, err)
+
+			want := make([]string, 0, len(unused[pt.Name]))
+			for _, key := range unused[pt.Name] {
+				want = append(want, fmt.Sprintf("%s asks for %s, but nothing uses it", pt.Name, key))
+			}
+			got := make([]string, 0, len(lint.Problems))
+			for _, p := range lint.Problems {
+				got = append(got, p.String())
+			}
+			assert.Equal(t, want, got)
+			assert.NotEmpty(t, lint.Vars)
+		})
+	}
+}
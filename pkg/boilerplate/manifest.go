@@ -0,0 +1,172 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ManifestFileName is the name of the manifest written into the root of every generated project.
+const ManifestFileName = ".boilerplate.json"
+
+// ManifestDir holds, below the project root, what the manifest keeps besides itself.
+const ManifestDir = ".boilerplate"
+
+// BaseDir holds, below the project root, every file as the templates rendered it before any hooks ran.
+const BaseDir = ManifestDir + "/base"
+
+// Manifest records how a project was generated, so it can be re-rendered against newer templates.
+type Manifest struct {
+	ProjectType    string            `json:"projectType"`
+	TemplateSource string            `json:"templateSource,omitempty"`
+	Version        string            `json:"version"`
+	Values         map[string]any    `json:"values"`
+	Files          map[string]string `json:"files"`
+
+	// Secrets are the keys of answers left out of Values, so they aren't kept in plain text.  Update renders them
+	// empty, so leaves alone any file that uses them.
+	Secrets []string `json:"secrets,omitempty"`
+
+	// Features lists the features added to the project since it was generated, see AddFeature.
+	Features []string `json:"features,omitempty"`
+}
+
+// HashContent returns the hex encoded sha256 of a file's content, as recorded in the manifest.
+func HashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadManifest reads the manifest from a generated project directory.
+func LoadManifest(fs afero.Fs, projDir string) (m Manifest, err error) {
+	path := filepath.Join(projDir, ManifestFileName)
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to read manifest %s", path)
+		return m, err
+	}
+
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to parse manifest %s", path)
+		return m, err
+	}
+
+	return m, err
+}
+
+// Write writes the manifest into a generated project directory.
+func (m Manifest) Write(fs afero.Fs, projDir string) (err error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		err = errors.Wrapf(err, "failed to marshal manifest")
+		return err
+	}
+
+	path := filepath.Join(projDir, ManifestFileName)
+	err = afero.WriteFile(fs, path, append(data, '\n'), 0644)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to write manifest %s", path)
+		return err
+	}
+
+	return err
+}
+
+// Rehash records the current content of every file the manifest tracks, e.g. once hooks have reformatted them, so
+// they aren't mistaken for the user's edits.  Files that have gone are dropped.
+func (m *Manifest) Rehash(fs afero.Fs, projDir string) (err error) {
+	for rel := range m.Files {
+		data, readErr := afero.ReadFile(fs, filepath.Join(projDir, rel))
+		if os.IsNotExist(readErr) {
+			delete(m.Files, rel)
+			continue
+		}
+		if readErr != nil {
+			err = errors.Wrapf(readErr, "failed to read %s", rel)
+			return err
+		}
+
+		m.Files[rel] = HashContent(data)
+	}
+
+	return err
+}
+
+// WriteBase replaces the copy of the project's rendering kept below BaseDir in projDir with files, keyed by path
+// relative to the project root.  It's what update merges the user's edits and the new rendering against.
+func WriteBase(fs afero.Fs, projDir string, files map[string][]byte) (err error) {
+	dir := filepath.Join(projDir, BaseDir)
+	err = fs.RemoveAll(dir)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to remove old base %s", dir)
+		return err
+	}
+
+	for _, rel := range sortedKeys(files) {
+		err = writeFileAll(fs, filepath.Join(dir, rel), files[rel])
+		if err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// ReadBase returns the files recorded by WriteBase, or nil if the project predates them.
+func ReadBase(fs afero.Fs, projDir string) (files map[string][]byte, err error) {
+	dir := filepath.Join(projDir, BaseDir)
+	exists, err := afero.DirExists(fs, dir)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to check for base %s", dir)
+		return files, err
+	}
+	if !exists {
+		return files, err
+	}
+
+	return readTree(fs, dir)
+}
+
+// isManifestPath reports whether rel, relative to the project root, is the manifest or part of its base.
+func isManifestPath(rel string) bool {
+	return rel == ManifestFileName || rel == ManifestDir || strings.HasPrefix(rel, ManifestDir+"/")
+}
+
+// withoutManifest drops the manifest and its base from files read by readTree.
+func withoutManifest(files map[string][]byte) map[string][]byte {
+	maps.DeleteFunc(files, func(rel string, _ []byte) bool { return isManifestPath(rel) })
+	return files
+}
+
+// withoutKeys returns a copy of vals without the given keys, or vals itself if there are none.
+func withoutKeys(vals map[string]any, keys []string) map[string]any {
+	if len(keys) == 0 {
+		return vals
+	}
+
+	out := make(map[string]any, len(vals))
+	for k, v := range vals {
+		if !slices.Contains(keys, k) {
+			out[k] = v
+		}
+	}
+
+	return out
+}
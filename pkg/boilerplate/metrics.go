@@ -0,0 +1,109 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds the Prometheus metrics for the server.
+type Metrics struct {
+	RequestsTotal      *prometheus.CounterVec
+	RequestErrorsTotal *prometheus.CounterVec
+	RequestDuration    *prometheus.HistogramVec
+	ProjectsGenerated  *prometheus.CounterVec
+}
+
+// NewMetrics creates and registers Prometheus metrics.
+func NewMetrics(namespace string) (metrics *Metrics) {
+	metrics = NewMetricsWithRegisterer(namespace, prometheus.DefaultRegisterer)
+	return metrics
+}
+
+// NewMetricsWithRegisterer creates metrics with a specific registerer (useful for testing).
+func NewMetricsWithRegisterer(namespace string, reg prometheus.Registerer) (metrics *Metrics) {
+	requestsTotal := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Total number of HTTP requests",
+		},
+		[]string{"endpoint", "method"},
+	)
+
+	requestErrorsTotal := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "request_errors_total",
+			Help:      "Total number of HTTP request errors",
+		},
+		[]string{"endpoint", "method", "error_type"},
+	)
+
+	requestDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request duration in seconds",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"endpoint", "method"},
+	)
+
+	projectsGenerated := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "projects_generated_total",
+			Help:      "Total number of projects generated",
+		},
+		[]string{"type", "format"},
+	)
+
+	// Register metrics
+	if reg != nil {
+		reg.MustRegister(requestsTotal, requestErrorsTotal, requestDuration, projectsGenerated)
+	}
+
+	metrics = &Metrics{
+		RequestsTotal:      requestsTotal,
+		RequestErrorsTotal: requestErrorsTotal,
+		RequestDuration:    requestDuration,
+		ProjectsGenerated:  projectsGenerated,
+	}
+	return metrics
+}
+
+// RecordRequest increments the request counter.
+func (m *Metrics) RecordRequest(endpoint, method string) {
+	if m != nil && m.RequestsTotal != nil {
+		m.RequestsTotal.WithLabelValues(endpoint, method).Inc()
+	}
+}
+
+// RecordRequestError increments the request error counter.
+func (m *Metrics) RecordRequestError(endpoint, method, errorType string) {
+	if m != nil && m.RequestErrorsTotal != nil {
+		m.RequestErrorsTotal.WithLabelValues(endpoint, method, errorType).Inc()
+	}
+}
+
+// RecordRequestDuration records the request duration.
+func (m *Metrics) RecordRequestDuration(endpoint, method string, duration float64) {
+	if m != nil && m.RequestDuration != nil {
+		m.RequestDuration.WithLabelValues(endpoint, method).Observe(duration)
+	}
+}
+
+// RecordProjectGenerated increments the generated project counter.
+func (m *Metrics) RecordProjectGenerated(projectType string, format ArchiveFormat) {
+	if m != nil && m.ProjectsGenerated != nil {
+		m.ProjectsGenerated.WithLabelValues(projectType, string(format)).Inc()
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"net/url"
 	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -54,6 +55,11 @@ type PromptValues interface {
 	AsMap() (data map[string]any, err error)
 }
 
+// PromptOrderer is implemented by params that ask their questions in their own order rather than the shared one.
+type PromptOrderer interface {
+	PromptOrder() []ParamPrompt
+}
+
 var nameValidations = []PromptValidation{ //nolint:gochecknoglobals // shared validation rules
 	{
 		IsValid: func(val string) bool {
@@ -97,10 +103,10 @@ var moduleValidations = []PromptValidation{ //nolint:gochecknoglobals // shared
 var envPrefix = []PromptValidation{ //nolint:gochecknoglobals // shared validation rules
 	{
 		IsValid: func(val string) bool {
-			isAlphaCap := regexp.MustCompile(`^[A-Z]+$`).MatchString
-			return isAlphaCap(val)
+			isEnvName := regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`).MatchString
+			return isEnvName(val)
 		},
-		InvalidMsg: "Error: Must contain capitalized letters only",
+		InvalidMsg: "Error: Must contain capital letters, digits and underscores only, starting with a letter",
 	},
 }
 
@@ -165,83 +171,106 @@ var semVerValidation = []PromptValidation{ //nolint:gochecknoglobals // shared v
 func commonPromptMessaging() map[ParamPrompt]Prompt {
 	return map[ParamPrompt]Prompt{
 		GoVersion: {
+			Order:        2,
 			PromptMsg:    "Enter a golang semver.",
 			InputFailMsg: "failed to read project description",
 			DefaultValue: goMajorAndMinor(),
 		},
 		DockerRegistry: {
+			Order:        16,
 			PromptMsg:    "Enter Docker registry.",
 			InputFailMsg: "failed to read Docker registry",
 		},
 		DockerProject: {
+			Order:        17,
 			PromptMsg:    "Enter Docker project.",
 			InputFailMsg: "failed to read Docker project",
 		},
 		ProjName: {
+			Order:        1,
 			PromptMsg:    "Enter a name for your new tool.",
 			InputFailMsg: "failed to read project name",
 			Validations:  nameValidations,
 		},
 		ProjPkgName: {
+			Order:        3,
 			PromptMsg:    "Enter the go module name for your new tool.",
 			InputFailMsg: "failed to read module name",
 			Validations:  moduleValidations,
+			DefaultValue: "github.com/something/{{.ProjectName}}",
 		},
 		ProjEnvPrefix: {
+			Order:        4,
 			PromptMsg:    "Enter environment variable prefix.",
 			InputFailMsg: "failed to read environment prefix",
 			Validations:  envPrefix,
+			DefaultValue: "{{screamingSnake .ProjectName}}",
 		},
 		ProjShortDesc: {
+			Order:        5,
 			PromptMsg:    "Enter a short project description.",
 			InputFailMsg: "failed to read project description",
 			DefaultValue: "boilerplate autogen project",
 		},
 		ProjLongDesc: {
+			Order:        6,
 			PromptMsg:    "Enter a long project description.",
 			InputFailMsg: "failed to read project description",
 			DefaultValue: "boilerplate autogen project",
 		},
 		ProjMaintainerName: {
+			Order:        11,
 			PromptMsg:    "Enter the project maintainer name.",
 			InputFailMsg: "failed to read project maintainer name",
-			DefaultValue: "you@example.com",
 		},
 		ProjMaintainerEmail: {
+			Order:        12,
 			PromptMsg:    "Enter the project maintainer email address.",
 			InputFailMsg: "failed to read project maintainer email address",
 			Validations:  emailValidation,
-			DefaultValue: "code@example.com",
 		},
 		ServerDefPort: {
+			Order:        13,
 			PromptMsg:    "Enter default server port.",
 			InputFailMsg: "failed to read server port",
 			Validations:  portValidation,
 		},
 		ServerShortDesc: {
+			Order:        7,
 			PromptMsg:    "Enter short server description.",
 			InputFailMsg: "failed to read server short description",
+			DefaultValue: "{{.ProjectShortDesc}}",
+			Hidden:       true,
 		},
 		ServerLongDesc: {
+			Order:        8,
 			PromptMsg:    "Enter long server description.",
 			InputFailMsg: "failed to read server long description",
+			DefaultValue: "{{.ProjectLongDesc}}",
+			Hidden:       true,
 		},
 		OwnerName: {
+			Order:        14,
 			PromptMsg:    "Enter owner name.",
 			InputFailMsg: "failed to read owner name",
+			DefaultValue: "{{.MaintainerName}}",
 		},
 		OwnerEmail: {
+			Order:        15,
 			PromptMsg:    "Enter owner email.",
 			InputFailMsg: "failed to read owner email",
 			Validations:  emailValidation,
+			DefaultValue: "{{.MaintainerEmail}}",
 		},
 		DbtRepo: {
+			Order:        9,
 			PromptMsg:    "Enter your DBT Repository URL.",
 			InputFailMsg: "failed to read dbt repo url",
 			Validations:  urlValidation,
 			DefaultValue: installedDbtRepo(),
 		},
 		ProjectVersion: {
+			Order:        10,
 			PromptMsg:    "Enter a semantic version.",
 			InputFailMsg: "failed to read semantic version",
 			Validations:  semVerValidation,
@@ -271,35 +300,96 @@ func goMajorAndMinor() (goMajMin string) {
 	return goMajMin
 }
 
-func paramsFromPrompts(r io.Reader, prompts map[ParamPrompt]Prompt, pvals PromptValues) (err error) {
-	values := pvals.Values()
-	for _, p := range []ParamPrompt{
-		ProjName,
-		GoVersion,
-		ProjPkgName,
-		ProjShortDesc,
-		ProjLongDesc,
-		DbtRepo,
-		ProjectVersion,
-		ProjMaintainerName,
-		ProjMaintainerEmail,
-		ServerDefPort,
-		OwnerName,
-		OwnerEmail,
-	} {
-		if _, exists := prompts[p]; !exists {
-			continue
+// promptOrderFor returns the order to ask a project type's questions in: its own, if it declares one, or else that of
+// the prompts' Order fields.  Either way, a prompt comes after those its default and condition refer to.
+func promptOrderFor(pvals PromptValues, prompts map[ParamPrompt]Prompt) (order []ParamPrompt) {
+	keys := make([]ParamPrompt, 0, len(prompts))
+	if o, ok := pvals.(PromptOrderer); ok {
+		keys = append(keys, o.PromptOrder()...)
+	}
+
+	declared := make(map[ParamPrompt]bool, len(keys))
+	for _, k := range keys {
+		declared[k] = true
+	}
+
+	var rest []ParamPrompt
+	for k := range prompts {
+		if !declared[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		if prompts[rest[i]].Order != prompts[rest[j]].Order {
+			return prompts[rest[i]].Order < prompts[rest[j]].Order
 		}
+		return rest[i] < rest[j]
+	})
+	keys = append(keys, rest...)
 
-		v := prompts[p]
-		v.From = r
+	pos := make(map[ParamPrompt]int, len(keys))
+	for i, k := range keys {
+		pos[k] = i
+	}
 
-		// Set dynamic default for package name based on project name
-		if p == ProjPkgName {
-			if projectName, exists := values[ProjName]; exists && projectName != nil && *projectName != "" {
-				v.DefaultValue = fmt.Sprintf("github.com/something/%s", *projectName)
-			}
+	placed := make(map[ParamPrompt]bool, len(keys))
+	var place func(k ParamPrompt)
+	place = func(k ParamPrompt) {
+		if _, ok := prompts[k]; !ok || placed[k] {
+			return
 		}
+		placed[k] = true
+
+		deps := prompts[k].dependencies()
+		sort.SliceStable(deps, func(i, j int) bool { return pos[deps[i]] < pos[deps[j]] })
+		for _, dep := range deps {
+			place(dep)
+		}
+		order = append(order, k)
+	}
+
+	for _, k := range keys {
+		place(k)
+	}
+
+	return order
+}
+
+// dependencies lists the answers a prompt's default and condition refer to.
+func (p Prompt) dependencies() (deps []ParamPrompt) {
+	uses := make(varUses)
+	_ = uses.addTemplate("default", p.DefaultValue, [2]string{}, 0)
+	if p.When != "" {
+		_ = uses.addTemplate("when", whenTemplate(p.When), [2]string{}, 0)
+	}
+
+	for _, name := range sortedKeys(uses) {
+		deps = append(deps, ParamPrompt(name))
+	}
+
+	return deps
+}
+
+// promptDefault returns the default for a prompt, resolved against the answers so far.
+func promptDefault(p Prompt, values map[ParamPrompt]*string) string {
+	return renderDefault(p.DefaultValue, values)
+}
+
+// promptApplies evaluates a prompt's condition against the answers so far.  Prompts without one always apply.
+func promptApplies(p Prompt, values map[ParamPrompt]*string) bool {
+	if p.When == "" {
+		return true
+	}
+
+	return truthy(renderDefault(whenTemplate(p.When), values))
+}
+
+func paramsFromPrompts(r io.Reader, prompts map[ParamPrompt]Prompt, pvals PromptValues) (err error) {
+	values := pvals.Values()
+	for _, p := range promptOrderFor(pvals, prompts) {
+		v := prompts[p]
+		v.From = r
+		v.DefaultValue = promptDefault(v, values)
 
 		dataVar, ok := values[p]
 		if !ok {
@@ -312,7 +402,12 @@ func paramsFromPrompts(r io.Reader, prompts map[ParamPrompt]Prompt, pvals Prompt
 			continue
 		}
 
-		if *dataVar != "" {
+		if *dataVar != "" || !promptApplies(v, values) {
+			continue
+		}
+
+		if v.Hidden {
+			*dataVar, _, _ = validateAnswer(v, v.DefaultValue)
 			continue
 		}
 
@@ -327,3 +422,36 @@ func paramsFromPrompts(r io.Reader, prompts map[ParamPrompt]Prompt, pvals Prompt
 
 	return err
 }
+
+// askPrompts asks prompter for every answer pvals still lacks, in order, skipping prompts that don't apply.  Hidden
+// prompts take their default, as does an empty answer.  An invalid answer fails as a ValidationErrors.
+func askPrompts(prompter Prompter, prompts map[ParamPrompt]Prompt, pvals PromptValues) (err error) {
+	values := pvals.Values()
+	for _, key := range promptOrderFor(pvals, prompts) {
+		p := prompts[key]
+		dataVar := values[key]
+		if dataVar == nil || *dataVar != "" || !promptApplies(p, values) {
+			continue
+		}
+
+		p.DefaultValue = promptDefault(p, values)
+		answer := p.DefaultValue
+		if !p.Hidden {
+			answer, err = prompter.Prompt(key, p)
+			if err != nil {
+				return fmt.Errorf("failed to ask for %s: %w", key, err)
+			}
+			if answer == "" {
+				answer = p.DefaultValue
+			}
+		}
+
+		canon, msg, ok := validateAnswer(p, answer)
+		if !ok {
+			return ValidationErrors{{Key: key, Msg: fmt.Sprintf("%s input: %q", msg, answer)}}
+		}
+		*dataVar = canon
+	}
+
+	return err
+}
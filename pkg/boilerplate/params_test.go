@@ -11,8 +11,11 @@ package boilerplate
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"strings"
 	"testing"
 )
@@ -306,3 +309,67 @@ func TestModuleValidations(t *testing.T) {
 		})
 	}
 }
+
+func TestPromptOrderFor(t *testing.T) {
+	order := promptOrderFor(&HeadlessServiceParams{}, GetHeadlessServiceParamsPromptMessaging())
+	assert.Equal(t, []ParamPrompt{ProjName, GoVersion, ProjPkgName, ProjEnvPrefix, ProjShortDesc}, order[:5])
+
+	// A prompt is asked after the ones its default and condition use, whatever their Order.
+	prompts := map[ParamPrompt]Prompt{
+		"Org":     {Order: 2},
+		"Name":    {Order: 3},
+		"Package": {Order: 1, DefaultValue: "{{.Org}}/{{.Name}}"},
+		"Auth":    {Order: 4, Kind: PromptBool},
+		"Issuer":  {Order: 0, When: ".Auth"},
+	}
+	assert.Equal(t, []ParamPrompt{"Auth", "Issuer", "Org", "Name", "Package"}, promptOrderFor(&SpecParams{}, prompts))
+}
+
+func TestHeadlessServiceParamsFromPrompts_ComputedDefaults(t *testing.T) {
+	stdin := bufio.NewReader(strings.NewReader("my-svc\n\n\n\n\n\nhttps://dbt\n\nTester\ntester@foo.com\n\n\n\n"))
+	data := &HeadlessServiceParams{}
+	require.NoError(t, HeadlessServiceParamsFromPrompts(data, stdin))
+	fmt.Printf("\n")
+
+	assert.Equal(t, "github.com/something/my-svc", data.ProjectPackage)
+	assert.Equal(t, "MY_SVC", data.EnvPrefix)
+	// Hidden, so never asked, but defaulted from the project description.
+	assert.Equal(t, data.ProjectShortDesc, data.ServerShortDesc)
+	assert.Equal(t, "Tester", data.OwnerName)
+	assert.Equal(t, "tester@foo.com", data.OwnerEmail)
+}
+
+func TestIndirectSelectionParams_ServerDescs(t *testing.T) {
+	vals := goldenAnswers()
+	vals[string(ProjShortDesc)] = "short"
+	vals[string(ProjLongDesc)] = "long"
+	res, err := Generate(context.Background(), Options{Type: IndirectSelectionType, Values: vals, OutFs: afero.NewMemMapFs()})
+	require.NoError(t, err)
+	assert.Equal(t, "short", res.Values[string(ServerShortDesc)])
+	assert.Equal(t, "long", res.Values[string(ServerLongDesc)])
+}
+
+func TestParamsFromPrompts_When(t *testing.T) {
+	spec, err := ParseTemplateSpec([]byte(`
+name: x
+prompts:
+  - key: EnableAuth
+    type: bool
+  - key: Issuer
+    when: .EnableAuth
+  - key: Done
+`))
+	require.NoError(t, err)
+
+	params := NewSpecParams(spec)
+	require.NoError(t, SpecParamsFromPrompts(params, bufio.NewReader(strings.NewReader("no\nyes\n"))))
+	fmt.Printf("\n")
+	assert.Equal(t, "false", *params.Values()["EnableAuth"])
+	assert.Equal(t, "", *params.Values()["Issuer"])
+	assert.Equal(t, "yes", *params.Values()["Done"])
+
+	params = NewSpecParams(spec)
+	require.NoError(t, SpecParamsFromPrompts(params, bufio.NewReader(strings.NewReader("y\nhttps://issuer\nyes\n"))))
+	fmt.Printf("\n")
+	assert.Equal(t, "https://issuer", *params.Values()["Issuer"])
+}
@@ -0,0 +1,145 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"fmt"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	ActionCreate    = "create"
+	ActionOverwrite = "overwrite"
+	ActionSkip      = "skip"
+	ActionBackup    = "backup"
+	// ActionConflict marks a file already there that the conflict policy refuses to touch, so BuildProject would fail.
+	ActionConflict = "conflict"
+)
+
+// PlannedFile describes a file BuildProject would write.
+type PlannedFile struct {
+	TemplPath string
+	Size      int64
+	Action    string
+}
+
+// Plan lists every file BuildProject would write into destDir, and whether it would create it, or what the conflict
+// policy would do with the file already there.
+func (w TmplWriter) Plan(destDir string) (plan []PlannedFile, err error) {
+	rendered, err := w.RenderProject(destDir)
+	if err != nil {
+		return plan, err
+	}
+
+	return w.planRendered(rendered, destDir)
+}
+
+// planRendered lists every file in rendered, staged for destDir, as Plan does.
+func (w TmplWriter) planRendered(rendered afero.Fs, destDir string) (plan []PlannedFile, err error) {
+	files, err := readTree(rendered, destDir)
+	if err != nil {
+		return plan, err
+	}
+
+	for _, rel := range sortedKeys(files) {
+		if isBaseFile(rel) {
+			continue
+		}
+
+		action := ActionCreate
+		_, statErr := w.OutFs.Stat(filepath.Join(destDir, rel))
+		if statErr == nil {
+			action = existingFileAction(w.OnConflict)
+		} else if !os.IsNotExist(statErr) {
+			return plan, fmt.Errorf("failed to stat %s: %w", rel, statErr)
+		}
+
+		plan = append(plan, PlannedFile{
+			TemplPath: rel,
+			Size:      int64(len(files[rel])),
+			Action:    action,
+		})
+	}
+
+	return plan, err
+}
+
+// Diff renders the project and returns a unified diff of every file against what already exists in destDir.
+func (w TmplWriter) Diff(destDir string) (diff string, err error) {
+	rendered, err := w.RenderProject(destDir)
+	if err != nil {
+		return diff, err
+	}
+
+	files, err := readTree(rendered, destDir)
+	if err != nil {
+		return diff, err
+	}
+
+	var sb strings.Builder
+	for _, rel := range sortedKeys(files) {
+		if isBaseFile(rel) {
+			continue
+		}
+
+		fromName := "a/" + rel
+		existing, readErr := afero.ReadFile(w.OutFs, filepath.Join(destDir, rel))
+		if readErr != nil {
+			if !os.IsNotExist(readErr) {
+				return diff, fmt.Errorf("failed to read %s: %w", rel, readErr)
+			}
+			fromName = "/dev/null"
+		}
+
+		if isBinary(existing) || isBinary(files[rel]) {
+			if HashContent(existing) != HashContent(files[rel]) {
+				fmt.Fprintf(&sb, "Binary files %s and b/%s differ\n", fromName, rel)
+			}
+			continue
+		}
+
+		sb.WriteString(UnifiedDiff(fromName, "b/"+rel, string(existing), string(files[rel])))
+	}
+
+	return sb.String(), err
+}
+
+// isBaseFile reports whether rel, relative to destDir, is one of the copies of the rendering the manifest keeps, which
+// mirror the files Plan and Diff already show.
+func isBaseFile(rel string) bool {
+	return strings.HasPrefix(rel, BaseDir+"/") || strings.Contains(rel, "/"+BaseDir+"/")
+}
+
+func existingFileAction(policy ConflictPolicy) string {
+	switch policy {
+	case OnConflictOverwrite:
+		return ActionOverwrite
+	case OnConflictSkip:
+		return ActionSkip
+	case OnConflictBackup:
+		return ActionBackup
+	}
+
+	return ActionConflict
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
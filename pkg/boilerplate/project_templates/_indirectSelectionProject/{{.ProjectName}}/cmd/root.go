@@ -70,7 +70,7 @@ func init() {
 
 	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "Enable debug logging")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose Output")
-	rootCmd.PersistentFlags().StringVarP(&serverAddress, "address", "a", "0.0.0.0:50001", "Server address")
+	rootCmd.PersistentFlags().StringVarP(&serverAddress, "address", "a", "0.0.0.0:{{.DefaultServerPort}}", "Server address")
 	rootCmd.PersistentFlags().BoolVarP(&plaintext, "plaintext", "", false, "connect without tls")
 	rootCmd.PersistentFlags().StringVarP(&pubKeyFile, "pubkey-file", "f", "~/.ssh/id_ed25519.pub", "File containing SSH public key to use for authentication.")
 	rootCmd.PersistentFlags().StringVarP(&username, "username", "u", "", "Username for authentication")
@@ -26,7 +26,7 @@ var serverCmd = &cobra.Command{
 	Long: `Start the {{.ProjectName}} gRPC server.
 
 The server will:
-- Listen for gRPC requests on the configured address (default: 0.0.0.0:50001)
+- Listen for gRPC requests on the configured address (default: 0.0.0.0:{{.DefaultServerPort}})
 - Serve Prometheus metrics on port 8080 at /metrics
 - Provide health checks at /healthz and /readyz
 - Authenticate requests using JWT-SSH tokens
@@ -65,7 +65,7 @@ func LoadConfig() (config Config, err error) {
 // setDefaults sets default configuration values.
 func setDefaults() {
 	// Server defaults
-	viper.SetDefault("grpc_address", "0.0.0.0:50001")
+	viper.SetDefault("grpc_address", "0.0.0.0:{{.DefaultServerPort}}")
 	viper.SetDefault("metrics_address", "0.0.0.0:8080")
 	viper.SetDefault("enable_reflection", false)
 	viper.SetDefault("plaintext", true)
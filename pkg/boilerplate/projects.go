@@ -12,10 +12,10 @@ package boilerplate
 import (
 	"embed"
 	"fmt"
-	"github.com/fatih/color"
 	"io"
+	"io/fs"
 	"log"
-	"os"
+	"strings"
 )
 
 const (
@@ -26,88 +26,120 @@ const (
 	IndirectSelectionType = "indirect-selection"
 )
 
-//go:embed project_templates/_cobraProject/*
+// A plain go:embed pattern leaves out files whose names start with . or _, so a project's .gitignore, .golangci.yml
+// and .github workflows would never be generated.  all: keeps them.
+
+//go:embed all:project_templates/_cobraProject
 var cobraProject embed.FS
 
-//go:embed project_templates/_headlessServiceProject/*
+//go:embed all:project_templates/_headlessServiceProject
 var headlessServiceProject embed.FS
 
-//go:embed project_templates/_spaProject/*
+//go:embed all:project_templates/_spaProject
 var spaProject embed.FS
 
 //go:embed all:project_templates/_indirectSelectionProject
 var indirectSelectionProject embed.FS
 
+// builtinFileRules apply to every built-in project type.  Embedded files don't keep their modes, so scripts are made
+// executable here.
+var builtinFileRules = []FileRule{ //nolint:gochecknoglobals // shared by the built-in types
+	{Pattern: "*.sh", Mode: "0755"},
+}
+
+func init() { //nolint:gochecknoinits // built-in project type registration
+	for _, pt := range []ProjectType{
+		NewProjectType(CobraProjectType, "A project based on the excellent Cobra CLI framework.",
+			cobraProject, "project_templates/_cobraProject",
+			func() *CobraCliToolParams { return &CobraCliToolParams{} }, GetCobraCliToolParamsPromptMessaging, CobraCliToolParamsFromPrompts),
+		NewProjectType(HeadlessServiceType, "A project implementing a standalone headless service useful for implementing APIs and the like.",
+			headlessServiceProject, "project_templates/_headlessServiceProject",
+			func() *HeadlessServiceParams { return &HeadlessServiceParams{} }, GetHeadlessServiceParamsPromptMessaging, HeadlessServiceParamsFromPrompts),
+		NewProjectType(SPAProjectType, "A project based on React, designed to be built as a self-contained single page application.",
+			spaProject, "project_templates/_spaProject",
+			NewSPAParams, commonPromptMessaging, SPAParamsFromPrompts),
+		NewProjectType(IndirectSelectionType, "A gRPC service demonstrating JWT-SSH authentication with per-method authorization.",
+			indirectSelectionProject, "project_templates/_indirectSelectionProject",
+			func() *IndirectSelectionParams { return &IndirectSelectionParams{} }, GetIndirectSelectionParamsPromptMessaging, IndirectSelectionParamsFromPrompts),
+	} {
+		pt.FileRules = append(pt.FileRules, builtinFileRules...)
+		pt.Hooks = DefaultHooks()
+		err := RegisterProjectType(pt)
+		if err != nil {
+			log.Fatalf("failed to register built-in project type: %v", err)
+		}
+	}
+}
+
 // GetProjectFs  Gets the embedded file system for the project of this type.
-func GetProjectFs(projType string) (embed.FS, string, error) {
-	switch projType {
-	case CobraProjectType:
-		return cobraProject, "project_templates/_cobraProject", nil
-	case HeadlessServiceType:
-		return headlessServiceProject, "project_templates/_headlessServiceProject", nil
-	case SPAProjectType:
-		return spaProject, "project_templates/_spaProject", nil
-	case IndirectSelectionType:
-		return indirectSelectionProject, "project_templates/_indirectSelectionProject", nil
+func GetProjectFs(projType string) (fs.FS, string, error) {
+	pt, ok := LookupProjectType(projType)
+	if !ok {
+		return nil, "", invalidTypeError(projType)
 	}
 
-	return embed.FS{}, "", fmt.Errorf("failed to detect embedded package: %s", projType)
+	return pt.Fs, pt.Root, nil
 }
 
 // ValidProjectTypes  Lists the valid project types.
 func ValidProjectTypes() []string {
-	return []string{
-		CobraProjectType,
-		HeadlessServiceType,
-		SPAProjectType,
-		IndirectSelectionType,
+	types := ProjectTypes()
+	names := make([]string, 0, len(types))
+	for _, pt := range types {
+		names = append(names, pt.Name)
 	}
+
+	return names
 }
 
 // IsValidProjectType  Returns true or false depending on whether the project is a supported type.
 func IsValidProjectType(v string) bool {
-	switch v {
-	case CobraProjectType:
-		return true
-	case HeadlessServiceType:
-		return true
-	case SPAProjectType:
-		return true
-	case IndirectSelectionType:
-		return true
-	}
-	return false
+	_, ok := LookupProjectType(v)
+	return ok
 }
 
-// promptForParamsWithRetry handles the retry loop for parameter collection.
-func promptForParamsWithRetry[T PromptValues](data T, promptFunc func(T, io.Reader) error) T {
-	for {
-		err := promptFunc(data, os.Stdin)
-		if err != nil {
-			fmt.Print(color.RedString("%s\n", err))
-		} else {
-			return data
-		}
-	}
+// PromptsForProject asks for every value a project type needs on stdin.  Use ProjectType.AskParams to ask some other
+// way.
+func PromptsForProject(proj string) (data PromptValues, err error) {
+	return ParamsForProject(proj, nil, false)
 }
 
-func PromptsForProject(proj string) (data PromptValues, err error) {
-	switch proj {
-	case CobraProjectType:
-		return promptForParamsWithRetry(&CobraCliToolParams{}, CobraCliToolParamsFromPrompts), nil
+// ParamsForProject fills the params for a project type from supplied values.  Values that are not supplied are
+// prompted for, unless noPrompt is set, in which case they fall back to their defaults.  Invalid values are reported
+// all at once rather than re-prompted.
+func ParamsForProject(proj string, vals map[string]string, noPrompt bool) (data PromptValues, err error) {
+	pt, ok := LookupProjectType(proj)
+	if !ok {
+		err = invalidTypeError(proj)
+		return data, err
+	}
 
-	case HeadlessServiceType:
-		return promptForParamsWithRetry(&HeadlessServiceParams{}, HeadlessServiceParamsFromPrompts), nil
+	return pt.Params(vals, noPrompt)
+}
 
-	case SPAProjectType:
-		return promptForParamsWithRetry(NewSPAParams(), SPAParamsFromPrompts), nil
+// paramsForProject applies supplied values to data, then either asks prompter for the remainder or, without one,
+// falls back to defaults.
+func paramsForProject[T PromptValues](data T, prompts map[ParamPrompt]Prompt, promptFunc func(T, io.Reader) error, vals map[string]string, prompter Prompter) (T, error) {
+	err := paramsFromValues(vals, prompts, data, prompter == nil)
+	if err != nil {
+		return data, err
+	}
 
-	case IndirectSelectionType:
-		return promptForParamsWithRetry(&IndirectSelectionParams{}, IndirectSelectionParamsFromPrompts), nil
+	if prompter != nil {
+		return data, askPrompts(prompter, prompts, data)
+	}
 
-	default:
-		log.Fatalf("unknown or unhandled project type. options are %s", ValidProjectTypes())
+	// Every prompted value is filled at this point, so the prompt func reads nothing and only applies its
+	// post-processing.
+	err = promptFunc(data, strings.NewReader(""))
+	if err != nil {
+		return data, err
 	}
 
-	return data, err
+	return data, nil
+}
+
+// invalidTypeError reports a project type that isn't registered.
+func invalidTypeError(name string) error {
+	return fmt.Errorf("%w %q: options are %s", ErrInvalidType, name, strings.Join(ValidProjectTypes(), ", "))
 }
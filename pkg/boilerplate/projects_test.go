@@ -10,11 +10,42 @@ THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLI
 package boilerplate
 
 import (
+	"context"
 	"embed"
+	"errors"
+	"fmt"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"testing/fstest"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// TestProjectFs_Dotfiles checks the embedded templates keep files whose names start with a dot, however deep, which a
+// plain go:embed pattern would drop.
+func TestProjectFs_Dotfiles(t *testing.T) {
+	for projType, dotfiles := range map[string][]string{
+		SPAProjectType:        {".github/workflows/ci.yaml", "configs/.env.example"},
+		HeadlessServiceType:   {".github/workflows/ci.yaml"},
+		IndirectSelectionType: {".github/workflows/ci.yaml"},
+	} {
+		t.Run(projType, func(t *testing.T) {
+			afs := afero.NewMemMapFs()
+			res, err := Generate(context.Background(), Options{Type: projType, Values: goldenAnswers(), OutFs: afs, Dest: "/out"})
+			require.NoError(t, err)
+
+			for _, rel := range dotfiles {
+				exists, existsErr := afero.Exists(afs, filepath.Join(res.ProjectDir, rel))
+				require.NoError(t, existsErr)
+				assert.True(t, exists, "%s not generated", rel)
+			}
+		})
+	}
+}
+
 func TestGetProjectFs(t *testing.T) {
 	for _, tc := range []struct {
 		Name    string
@@ -50,3 +81,101 @@ func TestGetProjectFs(t *testing.T) {
 		})
 	}
 }
+
+func TestValidProjectTypes(t *testing.T) {
+	assert.Equal(t, []string{CobraProjectType, HeadlessServiceType, SPAProjectType, IndirectSelectionType}, ValidProjectTypes()[:4])
+
+	for _, pt := range ProjectTypes() {
+		assert.NotEmpty(t, pt.Description, "project type %s has no description", pt.Name)
+	}
+}
+
+func TestRegisterProjectType(t *testing.T) {
+	var hookRan string
+	pt := NewProjectType("test-registered", "A type registered by a test.",
+		fstest.MapFS{"{{.ProjectName}}/README.md": {Data: []byte("# {{.ProjectName}}\n")}}, ".",
+		func() *CobraCliToolParams { return &CobraCliToolParams{} }, GetCobraCliToolParamsPromptMessaging, CobraCliToolParamsFromPrompts)
+	pt.Hooks = []Hook{
+		{
+			Name: "record",
+			Run: func(ctx context.Context, outFs afero.Fs, projDir string, vals map[string]any) error {
+				hookRan = projDir
+				return nil
+			},
+		},
+		{
+			Name: "fail",
+			Run: func(ctx context.Context, outFs afero.Fs, projDir string, vals map[string]any) error {
+				return errors.New("boom")
+			},
+		},
+		{
+			Name: "skip",
+			Run: func(ctx context.Context, outFs afero.Fs, projDir string, vals map[string]any) error {
+				return fmt.Errorf("%w: nothing to do", ErrHookSkipped)
+			},
+		},
+	}
+
+	require.NoError(t, RegisterProjectType(pt))
+	assert.Error(t, RegisterProjectType(pt), "duplicate registration should fail")
+	assert.Error(t, RegisterProjectType(ProjectType{Name: "incomplete"}))
+	assert.True(t, IsValidProjectType("test-registered"))
+	assert.Contains(t, ValidProjectTypes(), "test-registered")
+
+	data, err := ParamsForProject("test-registered", map[string]string{
+		"ProjectName":     "reg",
+		"DbtRepo":         "https://dbt",
+		"MaintainerName":  "Tester",
+		"MaintainerEmail": "tester@foo.com",
+	}, true)
+	require.NoError(t, err)
+	vals, err := data.AsMap()
+	require.NoError(t, err)
+
+	afs := afero.NewMemMapFs()
+	w, err := NewTmplWriter(afs, "test-registered", vals)
+	require.NoError(t, err)
+	require.NoError(t, w.BuildProject("/out"))
+
+	readme, err := afero.ReadFile(afs, "/out/reg/README.md")
+	require.NoError(t, err)
+	assert.Equal(t, "# reg\n", string(readme))
+
+	registered, ok := LookupProjectType("test-registered")
+	require.True(t, ok)
+	results, err := registered.RunHooks(context.Background(), afs, "/out/reg", vals)
+	assert.Equal(t, "/out/reg", hookRan)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hook fail failed")
+
+	// A failure doesn't stop the hooks after it.
+	require.Len(t, results, 3)
+	assert.Equal(t, HookOK, results[0].Status)
+	assert.Equal(t, HookFailed, results[1].Status)
+	assert.Equal(t, HookSkipped, results[2].Status)
+}
+
+func TestProjectType_Describe(t *testing.T) {
+	pt, ok := LookupProjectType(CobraProjectType)
+	require.True(t, ok)
+
+	desc, err := pt.Describe()
+	require.NoError(t, err)
+	assert.Equal(t, CobraProjectType, desc.Name)
+	require.NotEmpty(t, desc.Prompts)
+	assert.Equal(t, ProjName.String(), desc.Prompts[0].Key)
+	assert.NotEmpty(t, desc.Prompts[0].Validations)
+
+	var pkg PromptDescription
+	for _, p := range desc.Prompts {
+		if p.Key == ProjPkgName.String() {
+			pkg = p
+		}
+	}
+	assert.Equal(t, "github.com/something/<ProjectName>", pkg.Default)
+
+	assert.Contains(t, desc.Files, "<ProjectName>/go.mod")
+	assert.Contains(t, desc.Files, "<ProjectName>/cmd/root.go")
+	assert.Equal(t, []string{HookGofmt, HookGoModTidy, HookGitInit, HookPreCommit}, desc.Hooks)
+}
@@ -12,12 +12,38 @@ package boilerplate
 import (
 	"bufio"
 	"fmt"
+	"github.com/fatih/color"
 	"github.com/pkg/errors"
+	"golang.org/x/term"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 )
 
+// PromptKind is the kind of answer a prompt expects, which decides how it is asked for, read and checked.  Every
+// answer is still a string: bools are "true" or "false", ints are decimal, and multi-choice answers are joined with
+// commas.
+type PromptKind string
+
+const (
+	PromptString      PromptKind = "string"
+	PromptBool        PromptKind = "bool"
+	PromptInt         PromptKind = "int"
+	PromptChoice      PromptKind = "choice"
+	PromptMultiChoice PromptKind = "multi-choice"
+	PromptMultiline   PromptKind = "multiline"
+	PromptSecret      PromptKind = "secret"
+)
+
+// PromptKinds lists the kinds a prompt can be.
+func PromptKinds() []PromptKind {
+	return []PromptKind{PromptString, PromptBool, PromptInt, PromptChoice, PromptMultiChoice, PromptMultiline, PromptSecret}
+}
+
+// multilineEnd is typed on a line of its own to finish a multiline answer.
+const multilineEnd = "."
+
 type Prompt struct {
 	From         io.Reader
 	PromptMsg    string
@@ -25,6 +51,75 @@ type Prompt struct {
 	InputFailMsg string
 	DefaultValue string
 	Validations  []PromptValidation
+
+	// Kind is the kind of answer expected.  Empty means PromptString.
+	Kind PromptKind
+
+	// Choices are the answers allowed for PromptChoice and PromptMultiChoice.
+	Choices []string
+
+	// To is where the question is written.  Nil means stdout.
+	To io.Writer
+
+	// When is a condition over earlier answers, as for file rules, e.g. .EnableAuth.  If it doesn't hold, the prompt
+	// is skipped and its answer left empty.  Empty means always ask.
+	When string
+
+	// Hidden prompts are never asked.  They take their default unless answered in a values file or with --set.
+	Hidden bool
+
+	// Order places the prompt among a project type's others, lowest first, unless the type orders them itself.
+	// Whatever the order, a prompt is asked after those its default and condition refer to.
+	Order int
+}
+
+// Prompter asks for the answer to a question.  The prompt's default has been resolved against the answers so far,
+// and an empty answer takes it.  Answers are checked once returned, and an invalid one fails, so a prompter talking
+// to a person should check them itself with Prompt.Check, as PromptForInput does.
+type Prompter interface {
+	Prompt(key ParamPrompt, p Prompt) (answer string, err error)
+}
+
+// PrompterFunc adapts a function to a Prompter.
+type PrompterFunc func(key ParamPrompt, p Prompt) (answer string, err error)
+
+func (f PrompterFunc) Prompt(key ParamPrompt, p Prompt) (answer string, err error) {
+	return f(key, p)
+}
+
+// ReaderPrompter asks questions on stdout and reads the answers from a reader, asking again until it gets a valid
+// one.
+type ReaderPrompter struct {
+	// Out, if set, is where questions are written instead of stdout, e.g. when stdout carries the project itself.
+	Out io.Writer
+
+	from io.Reader
+}
+
+// NewReaderPrompter returns a prompter reading answers from r.  A terminal is read as is, so secrets aren't echoed;
+// anything else is buffered once for every question.
+func NewReaderPrompter(r io.Reader) *ReaderPrompter {
+	if f, ok := r.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return &ReaderPrompter{from: r}
+	}
+
+	return &ReaderPrompter{from: bufio.NewReader(r)}
+}
+
+func (rp *ReaderPrompter) Prompt(key ParamPrompt, p Prompt) (answer string, err error) {
+	p.From = rp.from
+	if rp.Out != nil {
+		p.To = rp.Out
+	}
+
+	for {
+		answer, err = PromptForInput(p)
+		if err == nil || errors.Is(err, io.EOF) {
+			return answer, err
+		}
+
+		fmt.Fprint(p.output(), color.RedString("%s\n", err))
+	}
 }
 
 type PromptValidation struct {
@@ -32,23 +127,25 @@ type PromptValidation struct {
 	InvalidMsg string
 }
 
+// PromptForInput asks a single question and reads the answer from p.From, or stdin.  An empty answer takes the
+// default.  Answers that aren't of the prompt's kind, or fail its validations, are returned as errors.
 func PromptForInput(p Prompt) (data string, err error) {
 	if p.From == nil {
 		p.From = os.Stdin
 	}
 
 	reader := bufio.NewReader(p.From)
-	var msg string
-	if p.DefaultValue != "" {
-		msg = fmt.Sprintf("%s [default: %s]:", p.PromptMsg, p.DefaultValue)
-	} else {
-		msg = fmt.Sprintf("%s:", p.PromptMsg)
-	}
-	msg = fmt.Sprintf("%s\n  value: ", msg)
-
-	fmt.Print(msg)
+	fmt.Fprint(p.output(), p.message())
 
-	input, err := reader.ReadString('\n')
+	var input string
+	switch p.Kind {
+	case PromptMultiline:
+		input, err = readMultiline(reader)
+	case PromptSecret:
+		input, err = readSecret(p.From, reader, p.output())
+	default:
+		input, err = reader.ReadString('\n')
+	}
 	if err != nil {
 		errMsg := "failed to read input name"
 		if p.InputFailMsg != "" {
@@ -58,17 +155,170 @@ func PromptForInput(p Prompt) (data string, err error) {
 		return data, err
 	}
 
-	data = strings.TrimRight(input, "\n")
+	data = strings.TrimRight(input, "\r\n")
 
 	if data == "" && p.DefaultValue != "" {
 		data = p.DefaultValue
 	}
 
-	for _, v := range p.Validations {
-		if !v.IsValid(data) {
-			return data, fmt.Errorf("%s input: %q", v.InvalidMsg, data)
+	return p.Check(data)
+}
+
+// Check checks that an answer is of the prompt's kind and passes its validations, returning it in canonical form,
+// e.g. "yes" as "true".
+func (p Prompt) Check(val string) (answer string, err error) {
+	answer, msg, ok := validateAnswer(p, val)
+	if !ok {
+		return answer, fmt.Errorf("%s input: %q", msg, val)
+	}
+
+	return answer, err
+}
+
+// output is where the question is written.
+func (p Prompt) output() io.Writer {
+	if p.To == nil {
+		return os.Stdout
+	}
+
+	return p.To
+}
+
+// message renders the question, with a hint at the answer expected and, for choices, the options.
+func (p Prompt) message() string {
+	var hint string
+	switch p.Kind {
+	case PromptBool:
+		hint = " [y/n]"
+		if def, ok := parseBool(p.DefaultValue); ok && def {
+			hint = " [Y/n]"
+		} else if ok {
+			hint = " [y/N]"
+		}
+	case PromptSecret:
+		if p.DefaultValue != "" {
+			hint = " [default: ********]"
+		}
+	default:
+		switch p.Kind {
+		case PromptMultiChoice:
+			hint = " (numbers or names, separated by commas)"
+		case PromptMultiline:
+			hint = fmt.Sprintf(" (end with a line holding only %s)", multilineEnd)
+		}
+		if p.DefaultValue != "" {
+			hint += fmt.Sprintf(" [default: %s]", p.DefaultValue)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s:\n", p.PromptMsg, hint)
+	if p.Kind == PromptChoice || p.Kind == PromptMultiChoice {
+		for i, c := range p.Choices {
+			fmt.Fprintf(&b, "  %d. %s\n", i+1, c)
+		}
+	}
+	b.WriteString("  value: ")
+
+	return b.String()
+}
+
+// readMultiline reads lines up to one holding only multilineEnd, or the end of the input.
+func readMultiline(reader *bufio.Reader) (text string, err error) {
+	var lines []string
+	for {
+		line, readErr := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if line == multilineEnd {
+			break
+		}
+
+		if readErr != nil {
+			if !errors.Is(readErr, io.EOF) || len(lines) == 0 && line == "" {
+				return text, readErr
+			}
+			lines = append(lines, line)
+			break
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n"), err
+}
+
+// readSecret reads an answer without echoing it if from is a terminal, or else a line as usual.
+func readSecret(from io.Reader, reader *bufio.Reader, to io.Writer) (secret string, err error) {
+	if f, ok := from.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		b, readErr := term.ReadPassword(int(f.Fd()))
+		fmt.Fprintln(to)
+		return string(b), readErr
+	}
+
+	return reader.ReadString('\n')
+}
+
+// parseAnswer checks that an answer is of the prompt's kind, returning it in canonical form, or else what's wrong.
+func (p Prompt) parseAnswer(val string) (answer string, msg string, ok bool) {
+	switch p.Kind {
+	case PromptBool:
+		b, isBool := parseBool(val)
+		if !isBool {
+			return val, "Error: Answer must be yes or no", false
+		}
+		return strconv.FormatBool(b), msg, true
+	case PromptInt:
+		n, err := strconv.Atoi(strings.TrimSpace(val))
+		if err != nil {
+			return val, "Error: Answer must be a whole number", false
+		}
+		return strconv.Itoa(n), msg, true
+	case PromptChoice:
+		c, found := p.choice(val)
+		if !found {
+			return val, fmt.Sprintf("Error: Answer must be one of %s", strings.Join(p.Choices, ", ")), false
+		}
+		return c, msg, true
+	case PromptMultiChoice:
+		picked := []string{}
+		for _, f := range strings.FieldsFunc(val, func(r rune) bool { return r == ',' || r == ' ' }) {
+			c, found := p.choice(f)
+			if !found {
+				return val, fmt.Sprintf("Error: Answers must be from %s", strings.Join(p.Choices, ", ")), false
+			}
+			picked = append(picked, c)
+		}
+		return strings.Join(picked, ","), msg, true
+	}
+
+	return val, msg, true
+}
+
+// choice resolves an answer to one of the prompt's choices, given either by name or by its number in the list.
+func (p Prompt) choice(val string) (choice string, ok bool) {
+	val = strings.TrimSpace(val)
+	for _, c := range p.Choices {
+		if c == val {
+			return c, true
 		}
 	}
 
-	return data, err
+	n, err := strconv.Atoi(val)
+	if err == nil && n >= 1 && n <= len(p.Choices) {
+		return p.Choices[n-1], true
+	}
+
+	return choice, false
+}
+
+// parseBool reads a yes or no answer.
+func parseBool(val string) (b bool, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(val)) {
+	case "y", "yes", "true", "t", "1", "on":
+		return true, true
+	case "n", "no", "false", "f", "0", "off":
+		return false, true
+	}
+
+	return false, false
 }
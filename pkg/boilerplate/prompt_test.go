@@ -0,0 +1,75 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptForInput_Kinds(t *testing.T) {
+	features := []string{"auth", "metrics", "tracing"}
+
+	for _, tc := range []struct {
+		Name    string
+		Prompt  Prompt
+		Input   string
+		Want    string
+		WantErr string
+	}{
+		{Name: "String", Prompt: Prompt{}, Input: "hello world\n", Want: "hello world"},
+		{Name: "String default", Prompt: Prompt{DefaultValue: "x"}, Input: "\n", Want: "x"},
+		{Name: "Bool yes", Prompt: Prompt{Kind: PromptBool}, Input: "Y\n", Want: "true"},
+		{Name: "Bool default", Prompt: Prompt{Kind: PromptBool, DefaultValue: "no"}, Input: "\n", Want: "false"},
+		{Name: "Bool invalid", Prompt: Prompt{Kind: PromptBool}, Input: "maybe\n", WantErr: "must be yes or no"},
+		{Name: "Int", Prompt: Prompt{Kind: PromptInt}, Input: " 42\n", Want: "42"},
+		{Name: "Int invalid", Prompt: Prompt{Kind: PromptInt}, Input: "4.2\n", WantErr: "whole number"},
+		{Name: "Choice by number", Prompt: Prompt{Kind: PromptChoice, Choices: features}, Input: "2\n", Want: "metrics"},
+		{Name: "Choice by name", Prompt: Prompt{Kind: PromptChoice, Choices: features}, Input: "tracing\n", Want: "tracing"},
+		{Name: "Choice invalid", Prompt: Prompt{Kind: PromptChoice, Choices: features}, Input: "4\n", WantErr: "must be one of auth, metrics, tracing"},
+		{Name: "Multi-choice", Prompt: Prompt{Kind: PromptMultiChoice, Choices: features}, Input: "1, tracing\n", Want: "auth,tracing"},
+		{Name: "Multi-choice none", Prompt: Prompt{Kind: PromptMultiChoice, Choices: features}, Input: "\n", Want: ""},
+		{Name: "Multi-choice invalid", Prompt: Prompt{Kind: PromptMultiChoice, Choices: features}, Input: "auth,logs\n", WantErr: "must be from"},
+		{Name: "Multiline", Prompt: Prompt{Kind: PromptMultiline}, Input: "line one\n\nline three\n.\nnext answer\n", Want: "line one\n\nline three"},
+		{Name: "Multiline to end of input", Prompt: Prompt{Kind: PromptMultiline}, Input: "only line", Want: "only line"},
+		{Name: "Secret from a pipe", Prompt: Prompt{Kind: PromptSecret}, Input: "hunter2\n", Want: "hunter2"},
+		{Name: "Validated", Prompt: Prompt{Kind: PromptInt, Validations: portValidation}, Input: "80\n", WantErr: "4 or 5 digit"},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Prompt.PromptMsg = "Question"
+			tc.Prompt.From = bufio.NewReader(strings.NewReader(tc.Input))
+
+			got, err := PromptForInput(tc.Prompt)
+			if tc.WantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.WantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.Want, got)
+		})
+	}
+}
+
+func TestPrompt_Message(t *testing.T) {
+	p := Prompt{PromptMsg: "Pick one", Kind: PromptChoice, Choices: []string{"a", "b"}, DefaultValue: "b"}
+	assert.Equal(t, "Pick one [default: b]:\n  1. a\n  2. b\n  value: ", p.message())
+
+	p = Prompt{PromptMsg: "Enable auth?", Kind: PromptBool, DefaultValue: "true"}
+	assert.Equal(t, "Enable auth? [Y/n]:\n  value: ", p.message())
+
+	p = Prompt{PromptMsg: "Token", Kind: PromptSecret, DefaultValue: "s3cret"}
+	assert.NotContains(t, p.message(), "s3cret")
+}
@@ -0,0 +1,207 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ProjectType is everything boilerplate needs to know to generate one kind of project.
+type ProjectType struct {
+	Name        string
+	Description string
+
+	// Fs holds the template tree at Root, e.g. Root/{{.ProjectName}}/...
+	Fs   fs.FS
+	Root string
+
+	// NewParams returns empty params for the type, Prompts the questions to fill them, and FromPrompts asks every
+	// question not yet answered.
+	NewParams   func() PromptValues
+	Prompts     func() map[ParamPrompt]Prompt
+	FromPrompts func(PromptValues, io.Reader) error
+
+	// FileRules conditionally leave files out of the generated project.
+	FileRules []FileRule
+
+	// Hooks run in order once the project has been written.
+	Hooks []Hook
+}
+
+// Hook is a step run after a project has been generated.  projDir is the root of the generated project in outFs.  A
+// hook with nothing to do returns an error wrapping ErrHookSkipped.
+type Hook struct {
+	Name string
+	Run  func(ctx context.Context, outFs afero.Fs, projDir string, vals map[string]any) error
+
+	// Command is the shell command a CommandHook runs, before rendering.  It is empty for other hooks.
+	Command string
+}
+
+// NewProjectType builds a ProjectType from typed params functions, like those of the built-in types.
+func NewProjectType[T PromptValues](name, description string, templFs fs.FS, root string, newParams func() T, prompts func() map[ParamPrompt]Prompt, fromPrompts func(T, io.Reader) error) ProjectType {
+	return ProjectType{
+		Name:        name,
+		Description: description,
+		Fs:          templFs,
+		Root:        root,
+		NewParams:   func() PromptValues { return newParams() },
+		Prompts:     prompts,
+		FromPrompts: func(p PromptValues, r io.Reader) error {
+			params, ok := p.(T)
+			if !ok {
+				return fmt.Errorf("params of type %T are not for project type %s", p, name)
+			}
+			return fromPrompts(params, r)
+		},
+	}
+}
+
+// ProjectTypeFromSpec builds a ProjectType from a template spec and the tree it came from.
+func ProjectTypeFromSpec(spec TemplateSpec, templFs fs.FS, root string) ProjectType {
+	pt := NewProjectType(spec.Name, spec.Description, templFs, root,
+		func() *SpecParams { return NewSpecParams(spec) }, spec.PromptMessaging, SpecParamsFromPrompts)
+
+	for _, h := range spec.Hooks {
+		// ParseTemplateSpec has already checked every hook.
+		if hook, err := h.hook(); err == nil {
+			pt.Hooks = append(pt.Hooks, hook)
+		}
+	}
+
+	return pt
+}
+
+// Params fills the params for the project type from supplied values.  Values that are not supplied are prompted for
+// on stdin, unless noPrompt is set, in which case they fall back to their defaults.
+func (pt ProjectType) Params(vals map[string]string, noPrompt bool) (data PromptValues, err error) {
+	var prompter Prompter
+	if !noPrompt {
+		prompter = NewReaderPrompter(os.Stdin)
+	}
+
+	return pt.AskParams(vals, nil, prompter)
+}
+
+// AskParams fills the params for the project type from supplied values, and asks prompter for the rest.  Without a
+// prompter, they fall back to their defaults.  defaults, keyed by prompt, replace the prompts' own, e.g. with the
+// user's from UserDefaults.Values.
+func (pt ProjectType) AskParams(vals map[string]string, defaults map[string]string, prompter Prompter) (data PromptValues, err error) {
+	return paramsForProject(pt.NewParams(), withDefaults(pt.Prompts(), defaults), pt.FromPrompts, vals, prompter)
+}
+
+// SecretKeys returns the keys of the project type's PromptSecret questions, sorted.
+func (pt ProjectType) SecretKeys() (keys []string) {
+	if pt.Prompts == nil {
+		return keys
+	}
+
+	for key, p := range pt.Prompts() {
+		if p.Kind == PromptSecret {
+			keys = append(keys, string(key))
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// RunHooks runs the project type's hooks in order against a generated project, reporting on each.  A failed hook
+// doesn't stop the rest; err says which failed.
+func (pt ProjectType) RunHooks(ctx context.Context, outFs afero.Fs, projDir string, vals map[string]any) (results []HookResult, err error) {
+	return runHooks(ctx, pt.Hooks, outFs, projDir, vals)
+}
+
+// runHooks runs hooks in order, reporting on each.
+func runHooks(ctx context.Context, hooks []Hook, outFs afero.Fs, projDir string, vals map[string]any) (results []HookResult, err error) {
+	var failed []string
+	for _, h := range hooks {
+		res := HookResult{Name: h.Name, Status: HookOK}
+
+		res.Err = h.Run(ctx, outFs, projDir, vals)
+		switch {
+		case errors.Is(res.Err, ErrHookSkipped):
+			res.Status = HookSkipped
+		case res.Err != nil:
+			res.Status = HookFailed
+			failed = append(failed, h.Name)
+		}
+
+		results = append(results, res)
+	}
+
+	if len(failed) > 0 {
+		err = fmt.Errorf("hook %s failed", strings.Join(failed, ", "))
+	}
+
+	return results, err
+}
+
+// registry holds every registered project type, in registration order.
+var registry = struct { //nolint:gochecknoglobals // project type registry
+	sync.RWMutex
+	types []ProjectType
+}{}
+
+// RegisterProjectType makes a project type available to gen and friends.  Programs importing this package can
+// register their own types, typically from an init func.
+func RegisterProjectType(pt ProjectType) (err error) {
+	if pt.Name == "" {
+		return errors.New("project type has no name")
+	}
+
+	if pt.Fs == nil || pt.NewParams == nil || pt.Prompts == nil || pt.FromPrompts == nil {
+		return fmt.Errorf("project type %s needs a template fs and params functions", pt.Name)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, existing := range registry.types {
+		if existing.Name == pt.Name {
+			return fmt.Errorf("project type %s is already registered", pt.Name)
+		}
+	}
+
+	registry.types = append(registry.types, pt)
+
+	return err
+}
+
+// LookupProjectType returns the registered project type with the given name.
+func LookupProjectType(name string) (pt ProjectType, ok bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	for _, t := range registry.types {
+		if t.Name == name {
+			return t, true
+		}
+	}
+
+	return pt, false
+}
+
+// ProjectTypes lists the registered project types in registration order.
+func ProjectTypes() []ProjectType {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return append([]ProjectType(nil), registry.types...)
+}
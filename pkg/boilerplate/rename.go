@@ -0,0 +1,101 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// templateSuffix marks a template file whose real name would upset the tools working on the template tree, e.g.
+// go.mod_ for a nested module, main.go_ for a file that shouldn't compile, or .gitignore_ for one git shouldn't read.
+// It is stripped from every path element that has it in the template tree when the project is generated, though not
+// from values that happen to end with it.  foo__ generates foo_.
+const templateSuffix = "_"
+
+// outputPath strips the template suffix from the elements of resolved whose unrendered counterparts in templ carry
+// it.  Elements match one for one, unless a value rendered to more or fewer of them, in which case only those before
+// the first and after the last element holding a template can be matched.
+func outputPath(templ, resolved string) string {
+	templElems := strings.Split(templ, "/")
+	elems := strings.Split(resolved, "/")
+
+	strip := func(t string, i int) {
+		if len(t) > len(templateSuffix) && strings.HasSuffix(t, templateSuffix) {
+			elems[i] = strings.TrimSuffix(elems[i], templateSuffix)
+		}
+	}
+
+	if len(templElems) == len(elems) {
+		for i, t := range templElems {
+			strip(t, i)
+		}
+		return strings.Join(elems, "/")
+	}
+
+	for i := 0; i < min(len(templElems), len(elems)) && !strings.Contains(templElems[i], "{{"); i++ {
+		strip(templElems[i], i)
+	}
+	for j := 1; j <= min(len(templElems), len(elems)) && !strings.Contains(templElems[len(templElems)-j], "{{"); j++ {
+		strip(templElems[len(templElems)-j], len(elems)-j)
+	}
+
+	return strings.Join(elems, "/")
+}
+
+// applyRenames works out the generated path of each resolved template path: the template suffix is stripped, then
+// the writer's rename map, keyed by path relative to the project root, applies.
+func (w TmplWriter) applyRenames() {
+	for i, fp := range w.FilePaths {
+		p := outputPath(fp.Path, fp.TemplPath)
+		name := outputPath(fp.Name, fp.TemplName)
+
+		top, rel, found := strings.Cut(p, "/")
+		if found {
+			if renamed, ok := renamePath(rel, w.Renames); ok {
+				p = path.Join(top, renamed)
+				name = path.Base(p)
+			}
+		}
+
+		w.FilePaths[i].TemplPath = p
+		w.FilePaths[i].TemplName = name
+	}
+}
+
+// renamePath moves rel if it, or a directory above it, is in renames.  The most specific entry wins.
+func renamePath(rel string, renames map[string]string) (renamed string, ok bool) {
+	best := ""
+	for from := range renames {
+		if (rel == from || strings.HasPrefix(rel, from+"/")) && len(from) > len(best) {
+			best = from
+		}
+	}
+
+	if best == "" {
+		return rel, false
+	}
+
+	return path.Join(renames[best], strings.TrimPrefix(rel, best)), true
+}
+
+// checkRenames reports a rename map entry that isn't a clean path inside the project.
+func checkRenames(renames map[string]string) error {
+	for from, to := range renames {
+		for _, p := range []string{from, to} {
+			if p == "" || path.IsAbs(p) || path.Clean(p) != p || p == ".." || strings.HasPrefix(p, "../") {
+				return fmt.Errorf("rename %q: %q is not a path relative to the project root", from, p)
+			}
+		}
+	}
+
+	return nil
+}
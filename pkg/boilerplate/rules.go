@@ -0,0 +1,171 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// FileRule applies to the generated files and directories matching Pattern.  Pattern uses path.Match syntax, and is
+// matched against paths relative to the project root, e.g. pkg/auth/oidc.go.  A pattern matching a directory applies
+// to everything in it.
+type FileRule struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+
+	// When is a template expression evaluated against the template values, e.g. .EnableAuth or eq .Auth "oidc".  If
+	// it is false, empty, or "no", matching paths are not generated.  An empty When always includes them.
+	When string `yaml:"when" json:"when,omitempty"`
+
+	// Mode sets the permissions of matching files, in octal, e.g. "0755" for scripts.  By default files are 0755 if
+	// executable in the template tree and 0644 otherwise.
+	Mode string `yaml:"mode" json:"mode,omitempty"`
+
+	// Verbatim copies matching files as they are, without evaluating them as templates.  Binary files always are.
+	Verbatim bool `yaml:"verbatim" json:"verbatim,omitempty"`
+}
+
+// check reports a rule that could never apply.
+func (r FileRule) check() error {
+	if r.Pattern == "" {
+		return fmt.Errorf("file rule has no pattern")
+	}
+
+	_, err := path.Match(r.Pattern, "")
+	if err != nil {
+		return fmt.Errorf("file rule %q has a bad pattern: %w", r.Pattern, err)
+	}
+
+	if r.When != "" {
+		_, err = newTemplate(r.Pattern).Parse(whenTemplate(r.When))
+		if err != nil {
+			return fmt.Errorf("file rule %q has a bad condition: %w", r.Pattern, err)
+		}
+	}
+
+	if r.Mode != "" {
+		_, err = parseMode(r.Mode)
+		if err != nil {
+			return fmt.Errorf("file rule %q: %w", r.Pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// parseMode parses an octal permission string like "0755".
+func parseMode(s string) (mode fs.FileMode, err error) {
+	m, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || m > 0777 {
+		return mode, fmt.Errorf("bad file mode %q: must be octal permissions such as 0644", s)
+	}
+
+	return fs.FileMode(m), nil
+}
+
+// matches reports whether rel, relative to the project root, or any directory above it matches the rule.
+func (r FileRule) matches(rel string) bool {
+	for p := rel; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		if ok, _ := path.Match(r.Pattern, p); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// included evaluates the rule's condition.
+func (r FileRule) included(vals map[string]any) (bool, error) {
+	if r.When == "" {
+		return true, nil
+	}
+
+	tmpl, err := newTemplate(r.Pattern).Parse(whenTemplate(r.When))
+	if err != nil {
+		return false, fmt.Errorf("file rule %q has a bad condition: %w", r.Pattern, err)
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, vals)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate condition for file rule %q: %w", r.Pattern, err)
+	}
+
+	return truthy(buf.String()), nil
+}
+
+// whenTemplate wraps a bare expression like .EnableAuth in delimiters.
+func whenTemplate(when string) string {
+	if strings.Contains(when, "{{") {
+		return when
+	}
+
+	return "{{" + when + "}}"
+}
+
+// truthy interprets a rendered condition.  Template values are mostly strings, so "false" and friends count as false.
+func truthy(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "false", "0", "no", "n", "off", "<no value>":
+		return false
+	}
+
+	return true
+}
+
+// applyFileRules marks the resolved paths that shouldn't be generated: those with a path element that rendered
+// empty, and those matching a rule whose condition is false.  Paths matching a rule that applies take its mode and
+// verbatim setting, the last matching rule winning.
+func (w TmplWriter) applyFileRules() error {
+	excluded := make([]bool, len(w.FileRules))
+	for i, r := range w.FileRules {
+		ok, err := r.included(w.TmplVals)
+		if err != nil {
+			return err
+		}
+		excluded[i] = !ok
+	}
+
+	for i, fp := range w.FilePaths {
+		if fp.TemplName == "" || strings.Contains("/"+fp.TemplPath+"/", "//") {
+			w.FilePaths[i].Excluded = true
+			continue
+		}
+
+		rel := fp.TemplPath
+		if _, after, found := strings.Cut(rel, "/"); found {
+			rel = after
+		}
+
+		for j, r := range w.FileRules {
+			if !r.matches(rel) {
+				continue
+			}
+
+			if excluded[j] {
+				w.FilePaths[i].Excluded = true
+				break
+			}
+
+			if r.Mode != "" {
+				w.FilePaths[i].Mode, _ = parseMode(r.Mode)
+			}
+			if r.Verbatim {
+				w.FilePaths[i].Verbatim = true
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/afero"
+	"net/http"
+	"path"
+	"time"
+)
+
+// maxRequestBytes caps the size of a generate request.
+const maxRequestBytes = 1 << 20
+
+// GenerateRequest is the body of a POST to /generate.  Values are keyed by prompt, and may be strings, numbers,
+// bools, or lists for multi-choice prompts.  Format is tgz, the default, tar or zip, and can also be given as the
+// format query parameter.
+type GenerateRequest struct {
+	Type   string         `json:"type"`
+	Values map[string]any `json:"values"`
+	Format ArchiveFormat  `json:"format,omitempty"`
+}
+
+// ErrorResponse is the body of a failed request.  Errors says what is wrong with each invalid value, keyed by
+// prompt, and File and Line locate a template that failed.
+type ErrorResponse struct {
+	Error  string                 `json:"error"`
+	Errors map[ParamPrompt]string `json:"errors,omitempty"`
+	File   string                 `json:"file,omitempty"`
+	Line   int                    `json:"line,omitempty"`
+}
+
+// Server serves the project types and generates projects over HTTP, for those who would rather fill in a form than
+// install boilerplate.  Projects are rendered in memory and returned as archives; nothing is written to disk.
+type Server struct {
+	server  *http.Server
+	metrics *Metrics
+}
+
+// NewServer creates a server listening on addr.
+func NewServer(addr string, metrics *Metrics) (server *Server) {
+	mux := http.NewServeMux()
+
+	s := &Server{
+		metrics: metrics,
+		server: &http.Server{
+			Addr:         addr,
+			Handler:      mux,
+			ReadTimeout:  30 * time.Second,
+			WriteTimeout: 2 * time.Minute,
+		},
+	}
+
+	// Register routes
+	mux.Handle("GET /metrics", promhttp.Handler())
+	mux.HandleFunc("GET /healthz", s.metricsMiddleware("/healthz", "GET", s.healthzHandler))
+	mux.HandleFunc("GET /readyz", s.metricsMiddleware("/readyz", "GET", s.readyzHandler))
+	mux.HandleFunc("GET /types", s.metricsMiddleware("/types", "GET", s.typesHandler))
+	mux.HandleFunc("POST /generate", s.metricsMiddleware("/generate", "POST", s.generateHandler))
+
+	server = s
+	return server
+}
+
+// Handler returns the server's routes, e.g. for testing.
+func (s *Server) Handler() http.Handler {
+	return s.server.Handler
+}
+
+// Start serves until the server is stopped.
+func (s *Server) Start() (err error) {
+	err = s.server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		err = fmt.Errorf("HTTP server failed to start: %w", err)
+		return err
+	}
+
+	err = nil
+	return err
+}
+
+// Stop gracefully stops the server.
+func (s *Server) Stop(ctx context.Context) (err error) {
+	err = s.server.Shutdown(ctx)
+	return err
+}
+
+// metricsMiddleware wraps HTTP handlers with metrics collection.
+func (s *Server) metricsMiddleware(endpoint, method string, next http.HandlerFunc) (handler http.HandlerFunc) {
+	handler = func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		s.metrics.RecordRequest(endpoint, method)
+		next(w, r)
+		s.metrics.RecordRequestDuration(endpoint, method, time.Since(start).Seconds())
+	}
+	return handler
+}
+
+// healthzHandler handles liveness probe requests.
+func (s *Server) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "timestamp": time.Now().UTC().Format(time.RFC3339)})
+}
+
+// readyzHandler handles readiness probe requests.
+func (s *Server) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready", "timestamp": time.Now().UTC().Format(time.RFC3339)})
+}
+
+// typesHandler lists every project type, with the questions it asks.
+func (s *Server) typesHandler(w http.ResponseWriter, r *http.Request) {
+	types := ProjectTypes()
+	descs := make([]TypeDescription, 0, len(types))
+	for _, pt := range types {
+		desc, err := pt.Describe()
+		if err != nil {
+			s.fail(w, r, "/types", http.StatusInternalServerError, "internal", ErrorResponse{Error: err.Error()})
+			return
+		}
+		descs = append(descs, desc)
+	}
+
+	writeJSON(w, http.StatusOK, descs)
+}
+
+// generateHandler generates a project from a GenerateRequest, and returns it as an archive.  Prompts not answered
+// take their defaults, and the project type's hooks run as they do for gen.
+func (s *Server) generateHandler(w http.ResponseWriter, r *http.Request) {
+	const endpoint = "/generate"
+
+	var req GenerateRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	dec.UseNumber()
+	err := dec.Decode(&req)
+	if err != nil {
+		s.fail(w, r, endpoint, http.StatusBadRequest, "bad_request", ErrorResponse{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = ArchiveFormat(r.URL.Query().Get("format"))
+	}
+	if req.Format == "" {
+		req.Format = ArchiveTgz
+	}
+	format, err := ParseArchiveFormat(string(req.Format))
+	if err != nil {
+		s.fail(w, r, endpoint, http.StatusBadRequest, "bad_request", ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	vals, err := FlattenValues(req.Values, "request")
+	if err != nil {
+		s.fail(w, r, endpoint, http.StatusBadRequest, "bad_request", ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	afs := afero.NewMemMapFs()
+	res, err := Generate(r.Context(), Options{Type: req.Type, Values: vals, OutFs: afs, Dest: "/", Hooks: true, Archive: true})
+	if err != nil {
+		s.generateFailed(w, r, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = WriteArchive(&buf, afs, res.ProjectDir, format)
+	if err != nil {
+		s.fail(w, r, endpoint, http.StatusInternalServerError, "internal", ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	s.metrics.RecordProjectGenerated(res.Type, format)
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(res.ProjectDir)+format.Ext()))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// generateFailed reports why Generate failed: invalid values, keyed by prompt, an unknown project type, or a
+// template that failed, and where.
+func (s *Server) generateFailed(w http.ResponseWriter, r *http.Request, err error) {
+	const endpoint = "/generate"
+	resp := ErrorResponse{Error: err.Error()}
+
+	var verrs ValidationErrors
+	var terr *TemplateError
+	switch {
+	case errors.As(err, &verrs):
+		resp.Error = "invalid values"
+		resp.Errors = make(map[ParamPrompt]string, len(verrs))
+		for _, ve := range verrs {
+			resp.Errors[ve.Key] = ve.Msg
+		}
+		s.fail(w, r, endpoint, http.StatusUnprocessableEntity, "validation", resp)
+	case errors.Is(err, ErrInvalidType):
+		s.fail(w, r, endpoint, http.StatusBadRequest, "invalid_type", resp)
+	case errors.As(err, &terr):
+		resp.File = terr.File
+		resp.Line = terr.Line
+		s.fail(w, r, endpoint, http.StatusInternalServerError, "template", resp)
+	default:
+		s.fail(w, r, endpoint, http.StatusInternalServerError, "internal", resp)
+	}
+}
+
+// fail records a failed request, and reports it as JSON.
+func (s *Server) fail(w http.ResponseWriter, r *http.Request, endpoint string, status int, errorType string, resp ErrorResponse) {
+	s.metrics.RecordRequestError(endpoint, r.Method, errorType)
+	writeJSON(w, status, resp)
+}
+
+// writeJSON writes v as the JSON body of a response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,127 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := NewServer("", NewMetricsWithRegisterer("test", prometheus.NewRegistry()))
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestServer_Types(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/healthz")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(ts.URL + "/types")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var types []TypeDescription
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&types))
+
+	names := make([]string, 0, len(types))
+	for _, td := range types {
+		names = append(names, td.Name)
+		assert.NotEmpty(t, td.Prompts, td.Name)
+	}
+	assert.Contains(t, names, HeadlessServiceType)
+}
+
+func TestServer_Generate(t *testing.T) {
+	ts := newTestServer(t)
+
+	body := `{"type": "cobra", "values": {"ProjectName": "demo", "DbtRepo": "https://dbt.example.com", "MaintainerName": "Tester", "MaintainerEmail": "tester@example.com"}}`
+	resp, err := http.Post(ts.URL+"/generate", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/gzip", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="demo.tar.gz"`, resp.Header.Get("Content-Disposition"))
+
+	gz, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	modes := make(map[string]int64)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		modes[hdr.Name] = hdr.Mode & 0777
+	}
+	assert.Contains(t, modes, "demo/main.go")
+	assert.Equal(t, int64(0755), modes["demo/pre-commit-hook.sh"])
+	for name := range modes {
+		assert.NotContains(t, name, ".git/")
+	}
+
+	resp, err = http.Post(ts.URL+"/generate?format=zip", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "application/zip", resp.Header.Get("Content-Type"))
+}
+
+func TestServer_GenerateErrors(t *testing.T) {
+	ts := newTestServer(t)
+
+	for _, tc := range []struct {
+		Name   string
+		Body   string
+		Status int
+		Errors map[ParamPrompt]string
+	}{
+		{Name: "Bad JSON", Body: `{"type":`, Status: http.StatusBadRequest},
+		{Name: "Unknown type", Body: `{"type": "nope"}`, Status: http.StatusBadRequest},
+		{Name: "Bad format", Body: `{"type": "cobra", "format": "rar"}`, Status: http.StatusBadRequest},
+		{
+			Name:   "Invalid values",
+			Body:   `{"type": "cobra", "values": {"ProjectName": "demo", "DbtRepo": "https://dbt", "MaintainerName": "Tester", "MaintainerEmail": "nope"}}`,
+			Status: http.StatusUnprocessableEntity,
+			Errors: map[ParamPrompt]string{ProjMaintainerEmail: `Error: Email must be a valid email address. input: "nope"`},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			resp, err := http.Post(ts.URL+"/generate", "application/json", strings.NewReader(tc.Body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tc.Status, resp.StatusCode)
+
+			var er ErrorResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&er))
+			assert.NotEmpty(t, er.Error)
+			assert.Equal(t, tc.Errors, er.Errors)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"fmt"
+	"github.com/spf13/afero"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// templateTestDataDir holds a template tree's own test data, next to its boilerplate.yaml: the answers
+// test-template renders the tree with, and the golden tree the result is compared against.
+const templateTestDataDir = "testdata"
+
+// SnapshotTime is the clock snapshots are rendered with, so they don't change from one year to the next.
+var SnapshotTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC) //nolint:gochecknoglobals // fixed clock
+
+// SnapshotMismatchError reports a rendered project that differs from its golden tree.
+type SnapshotMismatchError struct {
+	GoldenDir string
+	Diff      string
+}
+
+func (e *SnapshotMismatchError) Error() string {
+	return fmt.Sprintf("rendered project differs from golden tree %s:\n%s", e.GoldenDir, e.Diff)
+}
+
+// RenderSnapshot renders a project type into memory with fixed answers, as gen --no-prompt would, and returns the
+// directory the project is in.  The manifest is left out, as it records the boilerplate version.
+func RenderSnapshot(pt ProjectType, vals map[string]string) (rendered afero.Fs, root string, err error) {
+	params, err := pt.Params(vals, true)
+	if err != nil {
+		return rendered, root, fmt.Errorf("failed to get params for %s: %w", pt.Name, err)
+	}
+
+	datamap, err := params.AsMap()
+	if err != nil {
+		return rendered, root, fmt.Errorf("failed to export params for %s: %w", pt.Name, err)
+	}
+
+	w, err := NewTmplWriterFromFs(afero.NewMemMapFs(), pt.Fs, pt.Root, pt.Name, datamap)
+	if err != nil {
+		return rendered, root, err
+	}
+	w.FileRules = append(w.FileRules, pt.FileRules...)
+	w.Now = func() time.Time { return SnapshotTime }
+
+	rendered, err = w.RenderProject("/")
+	if err != nil {
+		return rendered, root, err
+	}
+
+	root = path.Join("/", w.ProjectRoot())
+	err = rendered.Remove(path.Join(root, ManifestFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return rendered, root, fmt.Errorf("failed to drop manifest from snapshot: %w", err)
+	}
+
+	err = rendered.RemoveAll(path.Join(root, ManifestDir))
+	if err != nil {
+		return rendered, root, fmt.Errorf("failed to drop manifest base from snapshot: %w", err)
+	}
+
+	return rendered, root, nil
+}
+
+// CompareSnapshot compares a rendered project with the golden tree at goldenDir, returning a *SnapshotMismatchError
+// with a unified diff if they differ.
+func CompareSnapshot(rendered afero.Fs, root string, goldenFs afero.Fs, goldenDir string) error {
+	exists, err := afero.DirExists(goldenFs, goldenDir)
+	if err != nil {
+		return fmt.Errorf("failed to check for golden tree %s: %w", goldenDir, err)
+	}
+	if !exists {
+		return fmt.Errorf("no golden tree at %s: update the snapshot to create it", goldenDir)
+	}
+
+	got, err := readTree(rendered, root)
+	if err != nil {
+		return err
+	}
+
+	want, err := readTree(goldenFs, goldenDir)
+	if err != nil {
+		return err
+	}
+
+	all := make(map[string]bool, len(got))
+	for rel := range got {
+		all[rel] = true
+	}
+	for rel := range want {
+		all[rel] = true
+	}
+
+	var sb strings.Builder
+	for _, rel := range sortedKeys(all) {
+		wantData, inGolden := want[rel]
+		gotData, inRendered := got[rel]
+
+		fromName, toName := "a/"+rel, "b/"+rel
+		if !inGolden {
+			fromName = "/dev/null"
+		}
+		if !inRendered {
+			toName = "/dev/null"
+		}
+
+		if isBinary(wantData) || isBinary(gotData) {
+			if HashContent(wantData) != HashContent(gotData) || inGolden != inRendered {
+				fmt.Fprintf(&sb, "Binary files %s and %s differ\n", fromName, toName)
+			}
+			continue
+		}
+
+		if inGolden && inRendered && string(wantData) == string(gotData) {
+			continue
+		}
+
+		sb.WriteString(UnifiedDiff(fromName, toName, string(wantData), string(gotData)))
+	}
+
+	if sb.Len() > 0 {
+		return &SnapshotMismatchError{GoldenDir: goldenDir, Diff: sb.String()}
+	}
+
+	return nil
+}
+
+// UpdateSnapshot replaces the golden tree at goldenDir with a rendered project.
+func UpdateSnapshot(rendered afero.Fs, root string, goldenFs afero.Fs, goldenDir string) error {
+	files, err := readTree(rendered, root)
+	if err != nil {
+		return err
+	}
+
+	err = goldenFs.RemoveAll(goldenDir)
+	if err != nil {
+		return fmt.Errorf("failed to clear golden tree %s: %w", goldenDir, err)
+	}
+
+	for rel, data := range files {
+		err = writeFileAll(goldenFs, filepath.Join(goldenDir, filepath.FromSlash(rel)), data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"errors"
+	"flag"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden trees in testdata/golden") //nolint:gochecknoglobals // test flag
+
+// goldenAnswers are the answers every built-in project type is rendered with for its snapshot.
+func goldenAnswers() map[string]string {
+	return map[string]string{
+		"ProjectName":     "demo",
+		"ProjectPackage":  "github.com/example/demo",
+		"DbtRepo":         "https://dbt.example.com",
+		"MaintainerName":  "Tester",
+		"MaintainerEmail": "tester@example.com",
+	}
+}
+
+// builtinProjectTypes lists the project types embedded in boilerplate, leaving out any registered by tests.
+func builtinProjectTypes() (types []ProjectType) {
+	for _, pt := range ProjectTypes() {
+		if strings.HasPrefix(pt.Root, "project_templates/") {
+			types = append(types, pt)
+		}
+	}
+
+	return types
+}
+
+// TestGolden renders every built-in project type and compares it with its golden tree in testdata/golden.  After an
+// intended template change, regenerate them with: go test ./pkg/boilerplate -run TestGolden -update
+func TestGolden(t *testing.T) {
+	for _, pt := range builtinProjectTypes() {
+		t.Run(pt.Name, func(t *testing.T) {
+			rendered, root, err := RenderSnapshot(pt, goldenAnswers())
+			require.NoError(t, err)
+
+			goldenDir := filepath.Join("testdata", "golden", pt.Name)
+			if *update {
+				require.NoError(t, UpdateSnapshot(rendered, root, afero.NewOsFs(), goldenDir))
+				return
+			}
+
+			err = CompareSnapshot(rendered, root, afero.NewOsFs(), goldenDir)
+			var mismatch *SnapshotMismatchError
+			if errors.As(err, &mismatch) {
+				t.Fatalf("%s output changed; if that's intended, rerun with -update\n%s", pt.Name, mismatch.Diff)
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCompareSnapshot(t *testing.T) {
+	rendered := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(rendered, "/demo/main.go", []byte("package main\n"), 0644))
+	require.NoError(t, afero.WriteFile(rendered, "/demo/new.txt", []byte("new\n"), 0644))
+
+	golden := afero.NewMemMapFs()
+	err := CompareSnapshot(rendered, "/demo", golden, "/golden")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no golden tree")
+
+	require.NoError(t, UpdateSnapshot(rendered, "/demo", golden, "/golden"))
+	require.NoError(t, CompareSnapshot(rendered, "/demo", golden, "/golden"))
+
+	require.NoError(t, afero.WriteFile(rendered, "/demo/main.go", []byte("package demo\n"), 0644))
+	require.NoError(t, rendered.Remove("/demo/new.txt"))
+	require.NoError(t, afero.WriteFile(golden, "/golden/old.txt", []byte("old\n"), 0644))
+
+	err = CompareSnapshot(rendered, "/demo", golden, "/golden")
+	var mismatch *SnapshotMismatchError
+	require.True(t, errors.As(err, &mismatch), "expected a mismatch, got: %v", err)
+	assert.Contains(t, mismatch.Diff, "--- a/main.go\n+++ b/main.go\n")
+	assert.Contains(t, mismatch.Diff, "-package main\n+package demo\n")
+	assert.Contains(t, mismatch.Diff, "--- a/new.txt\n+++ /dev/null\n")
+	assert.Contains(t, mismatch.Diff, "--- a/old.txt\n+++ /dev/null\n")
+}
@@ -0,0 +1,106 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/pkg/errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// TemplateDirFs opens a template tree in a local directory.  The directory holds the project tree itself, e.g.
+// dir/{{.ProjectName}}/...
+func TemplateDirFs(dir string) (tfs fs.FS, err error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to open template dir %s", dir)
+		return tfs, err
+	}
+
+	if !info.IsDir() {
+		err = fmt.Errorf("template dir %s is not a directory", dir)
+		return tfs, err
+	}
+
+	tfs = os.DirFS(dir)
+	return tfs, err
+}
+
+// CloneTemplateRepo clones a git repository of templates into a temporary directory using the git binary, and checks
+// out ref if one is given: a tag, commit, or branch of the repository.  Any URL git understands works, including a
+// path to a local bare repository.  The clone's .git directory is removed, leaving only the template tree.  The
+// returned cleanup func removes the clone.
+func CloneTemplateRepo(repoURL, ref string) (dir string, cleanup func(), err error) {
+	cleanup = func() {}
+
+	// A ref starting with a dash would be taken for an option.
+	if strings.HasPrefix(ref, "-") {
+		err = fmt.Errorf("invalid ref %q for template repo %s", ref, repoURL)
+		return dir, cleanup, err
+	}
+
+	dir, err = os.MkdirTemp("", "boilerplate-templates-")
+	if err != nil {
+		err = errors.Wrapf(err, "failed to create temp dir for template repo")
+		return dir, cleanup, err
+	}
+	cleanup = func() { _ = os.RemoveAll(dir) }
+
+	err = runGit("", "clone", "--quiet", "--", repoURL, dir)
+	if err != nil {
+		cleanup()
+		err = errors.Wrapf(err, "failed to clone template repo %s", repoURL)
+		return dir, func() {}, err
+	}
+
+	if ref != "" {
+		// Only the default branch is checked out locally, so other branches are found among the remote's.
+		err = runGit(dir, "checkout", "--quiet", "--detach", ref, "--")
+		if err != nil && runGit(dir, "checkout", "--quiet", "--detach", "origin/"+ref, "--") == nil {
+			err = nil
+		}
+		if err != nil {
+			cleanup()
+			err = errors.Wrapf(err, "failed to check out %s in template repo %s", ref, repoURL)
+			return dir, func() {}, err
+		}
+	}
+
+	// The repository's own metadata isn't part of the template tree, and rendering it would put a .git into the
+	// generated project.
+	err = os.RemoveAll(filepath.Join(dir, ".git"))
+	if err != nil {
+		cleanup()
+		err = errors.Wrapf(err, "failed to remove .git from template repo clone")
+		return dir, func() {}, err
+	}
+
+	return dir, cleanup, err
+}
+
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTemplateTree writes a minimal template tree into dir.
+func writeTemplateTree(t *testing.T, dir string, readme string) {
+	t.Helper()
+
+	files := map[string]string{
+		"{{.ProjectName}}/README.md":                     readme,
+		"{{.ProjectName}}/pkg/{{.ProjectName}}/main.go_": "package {{.ProjectName}}\n",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestTemplateDirFs(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplateTree(t, dir, "# {{.ProjectName}}\n")
+
+	tfs, err := TemplateDirFs(dir)
+	require.NoError(t, err)
+
+	afs := afero.NewMemMapFs()
+	w, err := NewTmplWriterFromFs(afs, tfs, ".", CobraProjectType, map[string]any{"ProjectName": "ext"})
+	require.NoError(t, err)
+	w.Source = dir
+
+	require.NoError(t, w.BuildProject("/out"))
+
+	readme, err := afero.ReadFile(afs, "/out/ext/README.md")
+	require.NoError(t, err)
+	assert.Equal(t, "# ext\n", string(readme))
+
+	m, err := LoadManifest(afs, "/out/ext")
+	require.NoError(t, err)
+	assert.Equal(t, dir, m.TemplateSource)
+
+	// A template dir that is a git working copy doesn't render its .git.
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/main\n"), 0644))
+	w, err = NewTmplWriterFromFs(afero.NewMemMapFs(), tfs, ".", CobraProjectType, map[string]any{"ProjectName": "ext"})
+	require.NoError(t, err)
+	for _, fp := range w.FilePaths {
+		assert.NotEqual(t, ".git", strings.SplitN(fp.Path, "/", 2)[0], "template walk includes .git")
+	}
+
+	_, err = UpdateProject(afs, "/out/ext", ConflictMarkers)
+	assert.Error(t, err, "updating from embedded templates should be refused")
+
+	_, err = TemplateDirFs(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestCloneTemplateRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	git := func(dir string, args ...string) {
+		t.Helper()
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "init.defaultBranch=main"}, args...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, out)
+	}
+
+	work := t.TempDir()
+	git(work, "init", "--quiet")
+	writeTemplateTree(t, work, "v1 {{.ProjectName}}\n")
+	git(work, "add", "-A")
+	git(work, "commit", "--quiet", "-m", "v1")
+	git(work, "tag", "v1")
+	git(work, "checkout", "--quiet", "-b", "next")
+	writeTemplateTree(t, work, "next {{.ProjectName}}\n")
+	git(work, "commit", "--quiet", "-am", "next")
+	git(work, "checkout", "--quiet", "main")
+	writeTemplateTree(t, work, "v2 {{.ProjectName}}\n")
+	git(work, "commit", "--quiet", "-am", "v2")
+
+	bare := filepath.Join(t.TempDir(), "templates.git")
+	git(work, "clone", "--quiet", "--bare", work, bare)
+
+	for _, tc := range []struct {
+		Name string
+		Ref  string
+		Want string
+	}{
+		{Name: "Default branch", Ref: "", Want: "v2 ext\n"},
+		{Name: "Tag", Ref: "v1", Want: "v1 ext\n"},
+		{Name: "Branch", Ref: "next", Want: "next ext\n"},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			dir, cleanup, err := CloneTemplateRepo(bare, tc.Ref)
+			require.NoError(t, err)
+			defer cleanup()
+
+			tfs, err := TemplateDirFs(dir)
+			require.NoError(t, err)
+
+			afs := afero.NewMemMapFs()
+			w, err := NewTmplWriterFromFs(afs, tfs, ".", CobraProjectType, map[string]any{"ProjectName": "ext"})
+			require.NoError(t, err)
+			require.NoError(t, w.BuildProject("/out"))
+
+			readme, err := afero.ReadFile(afs, "/out/ext/README.md")
+			require.NoError(t, err)
+			assert.Equal(t, tc.Want, string(readme))
+
+			_, err = os.Stat(filepath.Join(dir, ".git"))
+			assert.True(t, os.IsNotExist(err), ".git left in the clone")
+			exists, err := afero.DirExists(afs, "/out/.git")
+			require.NoError(t, err)
+			assert.False(t, exists, ".git rendered into the output")
+			assert.Equal(t, "ext", w.ProjectRoot())
+
+			cleanup()
+			_, err = os.Stat(dir)
+			assert.True(t, os.IsNotExist(err), "clone not cleaned up")
+		})
+	}
+
+	_, _, err := CloneTemplateRepo(filepath.Join(t.TempDir(), "missing.git"), "")
+	assert.Error(t, err)
+
+	_, _, err = CloneTemplateRepo(bare, "no-such-ref")
+	assert.ErrorContains(t, err, "failed to check out no-such-ref")
+
+	// Nor is a ref taken for an option.
+	_, _, err = CloneTemplateRepo(bare, "--orphan=x")
+	assert.ErrorContains(t, err, "invalid ref")
+}
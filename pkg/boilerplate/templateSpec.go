@@ -0,0 +1,418 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// TemplateSpecFileName is the name of the optional spec at the root of a template tree.  It is never copied into
+// generated projects.
+const TemplateSpecFileName = "boilerplate.yaml"
+
+// ErrNoTemplateSpec is returned by LoadTemplateSpec when a template tree has no spec.
+var ErrNoTemplateSpec = errors.New("template tree has no " + TemplateSpecFileName)
+
+// TemplateSpec declares a project type in data rather than Go code: its name, description, the questions it asks,
+// values derived from the answers, and rules for which files to generate.
+type TemplateSpec struct {
+	Name        string         `yaml:"name" json:"name"`
+	Description string         `yaml:"description" json:"description"`
+	Prompts     []PromptSpec   `yaml:"prompts" json:"prompts"`
+	Derived     []DerivedValue `yaml:"derived" json:"derived,omitempty"`
+	Files       []FileRule     `yaml:"files" json:"files,omitempty"`
+
+	// Delims replaces the {{ }} delimiters in the contents of every file in the tree, e.g. ["[[", "]]"].
+	Delims [2]string `yaml:"delims" json:"delims,omitempty"`
+
+	// Rename moves generated paths, relative to the project root, e.g. gitignore: .gitignore.
+	Rename map[string]string `yaml:"rename" json:"rename,omitempty"`
+
+	// Hooks run in order once the project has been written.
+	Hooks []HookSpec `yaml:"hooks" json:"hooks,omitempty"`
+}
+
+// PromptSpec declares a single question.  Default may be a template over earlier answers, e.g.
+// github.com/example/{{.ProjectName}}, and When a condition over them, e.g. .EnableAuth, which skips the question
+// unless it holds.  Both may only use answers declared earlier.  DependsOn lists the answers used, for the reader's
+// benefit.  Type is one of the PromptKinds, string by default; choice and multi-choice questions list their Choices.
+// Hidden questions are never asked, but take their default unless answered with --set or a values file.
+type PromptSpec struct {
+	Key        string   `yaml:"key" json:"key"`
+	Message    string   `yaml:"message" json:"message"`
+	Type       string   `yaml:"type" json:"type,omitempty"`
+	Choices    []string `yaml:"choices" json:"choices,omitempty"`
+	Default    string   `yaml:"default" json:"default,omitempty"`
+	When       string   `yaml:"when" json:"when,omitempty"`
+	Hidden     bool     `yaml:"hidden" json:"hidden,omitempty"`
+	Validation string   `yaml:"validation" json:"validation,omitempty"`
+	DependsOn  []string `yaml:"dependsOn" json:"dependsOn,omitempty"`
+}
+
+// DerivedValue is a template value computed from the answers rather than asked for.  Derived values are resolved in
+// order, so each can use the ones before it.
+type DerivedValue struct {
+	Key   string `yaml:"key" json:"key"`
+	Value string `yaml:"value" json:"value"`
+}
+
+// HookSpec declares a post-generation hook: either a built-in one by name, e.g. gofmt, or a shell command to run in
+// the project directory.  A plain string is shorthand for a built-in hook.
+type HookSpec struct {
+	Name string `yaml:"name" json:"name"`
+	Run  string `yaml:"run" json:"run,omitempty"`
+}
+
+// UnmarshalYAML accepts either a hook name or a mapping.
+func (h *HookSpec) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		h.Name = node.Value
+		return nil
+	}
+
+	type plain HookSpec
+	return node.Decode((*plain)(h))
+}
+
+// hook returns the Hook the spec declares.
+func (h HookSpec) hook() (hook Hook, err error) {
+	if h.Run != "" {
+		_, err = newTemplate(h.Name).Parse(h.Run)
+		if err != nil {
+			return hook, fmt.Errorf("hook %s has a bad command: %w", h.Name, err)
+		}
+		return CommandHook(h.Name, h.Run), err
+	}
+
+	hook, ok := BuiltinHook(h.Name)
+	if !ok {
+		return hook, fmt.Errorf("unknown hook %q: use one of %s, or give a command to run", h.Name, strings.Join(builtinHookNames(), ", "))
+	}
+
+	return hook, err
+}
+
+// builtinHookNames lists the built-in hooks a spec can name.
+func builtinHookNames() (names []string) {
+	for _, h := range DefaultHooks() {
+		names = append(names, h.Name)
+	}
+
+	return names
+}
+
+// validationKinds maps the validation names usable in a spec to the shared validation rules.
+var validationKinds = map[string][]PromptValidation{ //nolint:gochecknoglobals // shared validation rules
+	"name":      nameValidations,
+	"module":    moduleValidations,
+	"envPrefix": envPrefix,
+	"email":     emailValidation,
+	"port":      portValidation,
+	"url":       urlValidation,
+	"semver":    semVerValidation,
+}
+
+// ValidationKinds lists the validation names a spec may use.
+func ValidationKinds() []string {
+	return sortedKeys(validationKinds)
+}
+
+// LoadTemplateSpec reads and checks the spec at root in a template tree.  It returns ErrNoTemplateSpec if there
+// isn't one.
+func LoadTemplateSpec(templFs fs.FS, root string) (spec TemplateSpec, err error) {
+	specPath := path.Join(root, TemplateSpecFileName)
+	data, err := fs.ReadFile(templFs, specPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return spec, ErrNoTemplateSpec
+		}
+		return spec, fmt.Errorf("failed to read %s: %w", specPath, err)
+	}
+
+	spec, err = ParseTemplateSpec(data)
+	if err != nil {
+		return spec, fmt.Errorf("invalid %s: %w", specPath, err)
+	}
+
+	return spec, err
+}
+
+// ParseTemplateSpec parses a spec, checking that it names its type, that every key is unique, that validation kinds
+// exist, that templates parse, and that each prompt's dependencies are declared before it.
+func ParseTemplateSpec(data []byte) (spec TemplateSpec, err error) {
+	err = yaml.Unmarshal(data, &spec)
+	if err != nil {
+		return spec, fmt.Errorf("failed to parse spec: %w", err)
+	}
+
+	if spec.Name == "" {
+		return spec, errors.New("name is required")
+	}
+
+	if len(spec.Prompts) == 0 {
+		return spec, errors.New("at least one prompt is required")
+	}
+
+	declared := make(map[string]bool, len(spec.Prompts))
+	for _, p := range spec.Prompts {
+		declared[p.Key] = true
+	}
+
+	seen := make(map[string]bool, len(spec.Prompts))
+	for _, p := range spec.Prompts {
+		if p.Key == "" {
+			return spec, errors.New("every prompt needs a key")
+		}
+
+		if seen[p.Key] {
+			return spec, fmt.Errorf("prompt %q is declared twice", p.Key)
+		}
+
+		if _, ok := validationKinds[p.Validation]; p.Validation != "" && !ok {
+			return spec, fmt.Errorf("prompt %q has unknown validation %q: must be one of %s", p.Key, p.Validation, strings.Join(ValidationKinds(), ", "))
+		}
+
+		err = p.checkKind()
+		if err != nil {
+			return spec, err
+		}
+
+		for _, dep := range p.DependsOn {
+			if !seen[dep] {
+				return spec, fmt.Errorf("prompt %q depends on %q, which must be declared before it", p.Key, dep)
+			}
+		}
+
+		_, err = newTemplate(p.Key).Parse(p.Default)
+		if err != nil {
+			return spec, fmt.Errorf("prompt %q has an invalid default: %w", p.Key, err)
+		}
+
+		if p.When != "" {
+			_, err = newTemplate(p.Key).Parse(whenTemplate(p.When))
+			if err != nil {
+				return spec, fmt.Errorf("prompt %q has an invalid condition: %w", p.Key, err)
+			}
+		}
+
+		for _, dep := range (Prompt{DefaultValue: p.Default, When: p.When}).dependencies() {
+			if !seen[string(dep)] && declared[string(dep)] {
+				return spec, fmt.Errorf("prompt %q uses %q, which must be declared before it", p.Key, dep)
+			}
+		}
+
+		seen[p.Key] = true
+	}
+
+	for _, d := range spec.Derived {
+		if d.Key == "" {
+			return spec, errors.New("every derived value needs a key")
+		}
+
+		if seen[d.Key] {
+			return spec, fmt.Errorf("derived value %q is already declared", d.Key)
+		}
+
+		_, err = newTemplate(d.Key).Parse(d.Value)
+		if err != nil {
+			return spec, fmt.Errorf("derived value %q is invalid: %w", d.Key, err)
+		}
+
+		seen[d.Key] = true
+	}
+
+	if (spec.Delims[0] == "") != (spec.Delims[1] == "") {
+		return spec, errors.New("delims needs both a left and a right delimiter")
+	}
+
+	for _, r := range spec.Files {
+		err = r.check()
+		if err != nil {
+			return spec, err
+		}
+	}
+
+	err = checkRenames(spec.Rename)
+	if err != nil {
+		return spec, err
+	}
+
+	for _, h := range spec.Hooks {
+		if h.Name == "" {
+			return spec, errors.New("hook has no name")
+		}
+
+		_, err = h.hook()
+		if err != nil {
+			return spec, err
+		}
+	}
+
+	return spec, err
+}
+
+// checkKind checks a prompt's type, and that choices are given where, and only where, it takes them.
+func (p PromptSpec) checkKind() error {
+	kind := PromptKind(p.Type)
+	if p.Type == "" {
+		kind = PromptString
+	}
+
+	valid := false
+	for _, k := range PromptKinds() {
+		valid = valid || kind == k
+	}
+	if !valid {
+		kinds := make([]string, 0, len(PromptKinds()))
+		for _, k := range PromptKinds() {
+			kinds = append(kinds, string(k))
+		}
+		return fmt.Errorf("prompt %q has unknown type %q: must be one of %s", p.Key, p.Type, strings.Join(kinds, ", "))
+	}
+
+	takesChoices := kind == PromptChoice || kind == PromptMultiChoice
+	switch {
+	case takesChoices && len(p.Choices) == 0:
+		return fmt.Errorf("prompt %q is a %s, but has no choices", p.Key, kind)
+	case !takesChoices && len(p.Choices) > 0:
+		return fmt.Errorf("prompt %q has choices, but is a %s rather than a choice or multi-choice", p.Key, kind)
+	}
+
+	if p.Default != "" && !strings.Contains(p.Default, "{{") {
+		_, msg, ok := Prompt{Kind: kind, Choices: p.Choices}.parseAnswer(p.Default)
+		if !ok {
+			return fmt.Errorf("prompt %q has an invalid default: %s", p.Key, strings.TrimPrefix(msg, "Error: "))
+		}
+	}
+
+	return nil
+}
+
+// PromptMessaging returns the spec's prompts in the form the prompting code uses.
+func (s TemplateSpec) PromptMessaging() map[ParamPrompt]Prompt {
+	prompts := make(map[ParamPrompt]Prompt, len(s.Prompts))
+	for _, p := range s.Prompts {
+		prompts[ParamPrompt(p.Key)] = Prompt{
+			PromptMsg:    p.Message,
+			InputFailMsg: fmt.Sprintf("failed to read %s", p.Key),
+			DefaultValue: p.Default,
+			Validations:  validationKinds[p.Validation],
+			Kind:         PromptKind(p.Type),
+			Choices:      p.Choices,
+			When:         p.When,
+			Hidden:       p.Hidden,
+		}
+	}
+
+	return prompts
+}
+
+// SpecParams holds the answers for a project type declared by a TemplateSpec.
+type SpecParams struct {
+	Spec    TemplateSpec
+	answers map[ParamPrompt]*string
+}
+
+// NewSpecParams creates empty params for a spec.
+func NewSpecParams(spec TemplateSpec) *SpecParams {
+	p := &SpecParams{
+		Spec:    spec,
+		answers: make(map[ParamPrompt]*string, len(spec.Prompts)),
+	}
+
+	for _, ps := range spec.Prompts {
+		p.answers[ParamPrompt(ps.Key)] = new(string)
+	}
+
+	return p
+}
+
+func (p *SpecParams) Values() map[ParamPrompt]*string {
+	return p.answers
+}
+
+// PromptOrder asks questions in the order the spec declares them.
+func (p *SpecParams) PromptOrder() []ParamPrompt {
+	order := make([]ParamPrompt, 0, len(p.Spec.Prompts))
+	for _, ps := range p.Spec.Prompts {
+		order = append(order, ParamPrompt(ps.Key))
+	}
+
+	return order
+}
+
+// AsMap returns the answers plus the derived values.
+func (p *SpecParams) AsMap() (data map[string]any, err error) {
+	data = make(map[string]any, len(p.answers)+len(p.Spec.Derived))
+	for k, v := range p.answers {
+		data[string(k)] = *v
+	}
+
+	for _, d := range p.Spec.Derived {
+		var buf bytes.Buffer
+		tmpl, parseErr := newTemplate(d.Key).Option("missingkey=error").Parse(d.Value)
+		if parseErr != nil {
+			return data, fmt.Errorf("derived value %q is invalid: %w", d.Key, parseErr)
+		}
+
+		err = tmpl.Execute(&buf, data)
+		if err != nil {
+			return data, fmt.Errorf("failed to resolve derived value %q: %w", d.Key, err)
+		}
+
+		data[d.Key] = buf.String()
+	}
+
+	return data, err
+}
+
+// SpecParamsFromPrompts prompts for every unanswered question in the spec.
+func SpecParamsFromPrompts(params *SpecParams, r io.Reader) (err error) {
+	return paramsFromPrompts(r, params.Spec.PromptMessaging(), params)
+}
+
+// ParamsForSpec fills the params for a spec-declared project type, as ParamsForProject does for the built-in ones.
+func ParamsForSpec(spec TemplateSpec, vals map[string]string, noPrompt bool) (data PromptValues, err error) {
+	return ProjectTypeFromSpec(spec, nil, "").Params(vals, noPrompt)
+}
+
+// renderDefault resolves a default that refers to earlier answers.  Answers not given yet resolve to "".
+func renderDefault(def string, values map[ParamPrompt]*string) string {
+	if !strings.Contains(def, "{{") {
+		return def
+	}
+
+	answers := make(map[string]string, len(values))
+	for k, v := range values {
+		if v != nil {
+			answers[string(k)] = *v
+		}
+	}
+
+	tmpl, err := newTemplate("default").Option("missingkey=zero").Parse(def)
+	if err != nil {
+		return def
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, answers)
+	if err != nil {
+		return def
+	}
+
+	return buf.String()
+}
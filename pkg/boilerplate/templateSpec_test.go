@@ -0,0 +1,158 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+name: lambda
+description: An AWS lambda function.
+prompts:
+  - key: ProjectName
+    message: Enter a name for your function.
+    validation: name
+  - key: ProjectPackage
+    message: Enter the go module name.
+    default: github.com/example/{{.ProjectName}}
+    validation: module
+    dependsOn: [ProjectName]
+  - key: MemorySize
+    message: Enter the memory size in MB.
+    default: "128"
+derived:
+  - key: Handler
+    value: "{{.ProjectName}}-handler"
+`
+
+func TestParseTemplateSpec(t *testing.T) {
+	spec, err := ParseTemplateSpec([]byte(testSpec))
+	require.NoError(t, err)
+	assert.Equal(t, "lambda", spec.Name)
+	assert.Len(t, spec.Prompts, 3)
+
+	spec, err = ParseTemplateSpec([]byte("name: x\nprompts: [{key: A}]\ndelims: ['[[', ']]']"))
+	require.NoError(t, err)
+	assert.Equal(t, [2]string{"[[", "]]"}, spec.Delims)
+
+	spec, err = ParseTemplateSpec([]byte("name: x\nprompts: [{key: A, type: multi-choice, choices: [a, b], default: b}, {key: B, type: bool}]"))
+	require.NoError(t, err)
+	data, err := ParamsForSpec(spec, map[string]string{"B": "yes"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, "b", *data.Values()["A"])
+	assert.Equal(t, "true", *data.Values()["B"])
+
+	spec, err = ParseTemplateSpec([]byte("name: x\nprompts: [{key: A}]\nhooks: [gofmt, {name: gen, run: make gen}]"))
+	require.NoError(t, err)
+	assert.Equal(t, []HookSpec{{Name: HookGofmt}, {Name: "gen", Run: "make gen"}}, spec.Hooks)
+	assert.Len(t, ProjectTypeFromSpec(spec, nil, ".").Hooks, 2)
+
+	for _, tc := range []struct {
+		Name string
+		Spec string
+		Want string
+	}{
+		{Name: "No name", Spec: "prompts: [{key: A}]", Want: "name is required"},
+		{Name: "No prompts", Spec: "name: x", Want: "at least one prompt"},
+		{Name: "Duplicate key", Spec: "name: x\nprompts: [{key: A}, {key: A}]", Want: "declared twice"},
+		{Name: "Unknown validation", Spec: "name: x\nprompts: [{key: A, validation: nope}]", Want: "unknown validation"},
+		{Name: "Dependency out of order", Spec: "name: x\nprompts: [{key: A, dependsOn: [B]}, {key: B}]", Want: "must be declared before it"},
+		{Name: "Default uses a later answer", Spec: "name: x\nprompts: [{key: A, default: '{{.B}}'}, {key: B}]", Want: "must be declared before it"},
+		{Name: "Condition uses a later answer", Spec: "name: x\nprompts: [{key: A, when: .B}, {key: B}]", Want: "must be declared before it"},
+		{Name: "Bad condition", Spec: "name: x\nprompts: [{key: A, when: '{{if'}]", Want: "invalid condition"},
+		{Name: "Bad default", Spec: "name: x\nprompts: [{key: A, default: '{{.B'}]", Want: "invalid default"},
+		{Name: "Half delims", Spec: "name: x\nprompts: [{key: A}]\ndelims: ['[[', '']", Want: "both a left and a right"},
+		{Name: "Bad file pattern", Spec: "name: x\nprompts: [{key: A}]\nfiles: [{pattern: '[', when: .A}]", Want: "bad pattern"},
+		{Name: "Unknown hook", Spec: "name: x\nprompts: [{key: A}]\nhooks: [lint]", Want: "unknown hook"},
+		{Name: "Unknown type", Spec: "name: x\nprompts: [{key: A, type: date}]", Want: "unknown type"},
+		{Name: "Choice without choices", Spec: "name: x\nprompts: [{key: A, type: choice}]", Want: "has no choices"},
+		{Name: "Choices on a string", Spec: "name: x\nprompts: [{key: A, choices: [a, b]}]", Want: "has choices"},
+		{Name: "Default not a choice", Spec: "name: x\nprompts: [{key: A, type: choice, choices: [a, b], default: c}]", Want: "invalid default"},
+		{Name: "Derived shadows prompt", Spec: "name: x\nprompts: [{key: A}]\nderived: [{key: A, value: b}]", Want: "already declared"},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := ParseTemplateSpec([]byte(tc.Spec))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.Want)
+		})
+	}
+}
+
+func TestParamsForSpec(t *testing.T) {
+	spec, err := ParseTemplateSpec([]byte(testSpec))
+	require.NoError(t, err)
+
+	data, err := ParamsForSpec(spec, map[string]string{"ProjectName": "fn"}, true)
+	require.NoError(t, err)
+
+	dataMap, err := data.AsMap()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"ProjectName":    "fn",
+		"ProjectPackage": "github.com/example/fn",
+		"MemorySize":     "128",
+		"Handler":        "fn-handler",
+	}, dataMap)
+
+	_, err = ParamsForSpec(spec, map[string]string{"ProjectName": "bad name", "Unknown": "x"}, true)
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs), "expected ValidationErrors, got %v", err)
+	// The bad name also spoils the ProjectPackage default computed from it.
+	assert.Len(t, verrs, 3)
+
+	// Interactive answers are asked for in declared order, with defaults resolved against earlier answers.
+	params := NewSpecParams(spec)
+	err = SpecParamsFromPrompts(params, bufio.NewReader(strings.NewReader("fn2\n\n256\n")))
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/example/fn2", *params.Values()["ProjectPackage"])
+	assert.Equal(t, "256", *params.Values()["MemorySize"])
+}
+
+func TestTemplateSpec_NotRendered(t *testing.T) {
+	templFs := fstest.MapFS{
+		TemplateSpecFileName:              {Data: []byte(testSpec)},
+		"{{.ProjectName}}/main.go_":       {Data: []byte("package main // {{.Handler}}\n")},
+		"{{.ProjectName}}/memory.txt":     {Data: []byte("{{.MemorySize}}\n")},
+		"{{.ProjectName}}/docs/README.md": {Data: []byte("# {{.ProjectName}}\n")},
+	}
+
+	spec, err := LoadTemplateSpec(templFs, ".")
+	require.NoError(t, err)
+
+	data, err := ParamsForSpec(spec, map[string]string{"ProjectName": "fn"}, true)
+	require.NoError(t, err)
+	dataMap, err := data.AsMap()
+	require.NoError(t, err)
+
+	afs := afero.NewMemMapFs()
+	w, err := NewTmplWriterFromFs(afs, templFs, ".", spec.Name, dataMap)
+	require.NoError(t, err)
+	require.NoError(t, w.BuildProject("/out"))
+
+	exists, err := afero.Exists(afs, "/out/"+TemplateSpecFileName)
+	require.NoError(t, err)
+	assert.False(t, exists, "template spec copied into the output")
+
+	memory, err := afero.ReadFile(afs, "/out/fn/memory.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "128\n", string(memory))
+
+	_, err = LoadTemplateSpec(fstest.MapFS{}, ".")
+	assert.ErrorIs(t, err, ErrNoTemplateSpec)
+}
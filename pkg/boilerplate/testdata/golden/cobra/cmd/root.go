@@ -0,0 +1,72 @@
+package cmd
+import (
+    "fmt"
+    "os"
+    "github.com/example/demo/pkg/demo"
+    homedir "github.com/mitchellh/go-homedir"
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+)
+
+var cfgFile string
+
+// rootCmd represents the base command when called without any subcommands
+var rootCmd = &cobra.Command{
+    Use:   "demo",
+    Short: "boilerplate autogen project",
+    // Note:  Change the following line to backticks for long multiline descriptions.
+    Long: "boilerplate autogen project",
+    // Uncomment the following line if your bare application
+    // has an action associated with it:
+    Run: func(cmd *cobra.Command, args []string) {
+        demo.Run()
+    },
+}
+
+// Execute adds all child commands to the root command and sets flags appropriately.
+// This is called by main.main(). It only needs to happen once to the rootCmd.
+func Execute() {
+    if err := rootCmd.Execute(); err != nil {
+        fmt.Println(err)
+        os.Exit(1)
+    }
+}
+
+func init() {
+    cobra.OnInitialize(initConfig)
+
+    // Here you will define your flags and configuration settings.
+    // Cobra supports persistent flags, which, if defined here,
+    // will be global for your application.
+    rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.demo.yaml)")
+
+    // Cobra also supports local flags, which will only run
+    // when this action is called directly.
+    rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
+
+// initConfig reads in config file and ENV variables if set.
+func initConfig() {
+    if cfgFile != "" {
+        // Use config file from the flag.
+        viper.SetConfigFile(cfgFile)
+    } else {
+        // Find home directory.
+        home, err := homedir.Dir()
+        if err != nil {
+            fmt.Println(err)
+            os.Exit(1)
+        }
+
+        // Search config in home directory with name ".demo" (without extension).
+        viper.AddConfigPath(home)
+        viper.SetConfigName(".demo")
+    }
+
+    viper.AutomaticEnv() // read in environment variables that match
+
+    // If a config file is found, read it in.
+    if err := viper.ReadInConfig(); err == nil {
+    fmt.Println("Using config file:", viper.ConfigFileUsed())
+    }
+}
@@ -0,0 +1,30 @@
+module github.com/example/demo
+
+go 1.27
+
+require (
+    github.com/mitchellh/go-homedir v1.1.0
+    github.com/spf13/cobra v1.1.3
+    github.com/spf13/viper v1.10.1
+
+)
+
+require (
+
+    github.com/fsnotify/fsnotify v1.5.1 // indirect
+    github.com/hashicorp/hcl v1.0.0 // indirect
+    github.com/inconshreveable/mousetrap v1.0.0 // indirect
+    github.com/magiconair/properties v1.8.5 // indirect
+    github.com/mitchellh/mapstructure v1.4.3 // indirect
+    github.com/pelletier/go-toml v1.9.4 // indirect
+    github.com/spf13/afero v1.8.1 // indirect
+    github.com/spf13/cast v1.4.1 // indirect
+    github.com/spf13/jwalterweatherman v1.1.0 // indirect
+    github.com/spf13/pflag v1.0.5 // indirect
+    github.com/subosito/gotenv v1.2.0 // indirect
+    golang.org/x/sys v0.0.0-20211210111614-af8b64212486 // indirect
+    golang.org/x/text v0.3.7 // indirect
+    gopkg.in/ini.v1 v1.66.2 // indirect
+    gopkg.in/yaml.v2 v2.4.0 // indirect
+
+)
@@ -0,0 +1,9 @@
+package demo
+
+import (
+    "fmt"
+)
+
+func Run() {
+    fmt.Println("It works")
+}
\ No newline at end of file
@@ -0,0 +1,2 @@
+#!/usr/bin/env bash
+/usr/local/go/bin/gofmt -w ./
\ No newline at end of file
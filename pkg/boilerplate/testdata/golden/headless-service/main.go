@@ -0,0 +1,10 @@
+/*
+Copyright © 0.1.0 Tester <tester@example.com>
+*/
+package main
+
+import "github.com/example/demo/cmd"
+
+func main() {
+	cmd.Execute()
+}
@@ -0,0 +1,9 @@
+package main
+
+import (
+	"github.com/example/demo/cmd"
+)
+
+func main() {
+	cmd.Execute()
+}
@@ -0,0 +1,208 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.31.1
+// source: service.proto
+
+package demo
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	demo_Foo_FullMethodName = "/demo.demo/Foo"
+	demo_Bar_FullMethodName = "/demo.demo/Bar"
+	demo_Baz_FullMethodName = "/demo.demo/Baz"
+)
+
+// demoClient is the client API for demo service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// demo service demonstrates simple method calls
+type demoClient interface {
+	// Foo method demonstrates a simple call
+	Foo(ctx context.Context, in *FooRequest, opts ...grpc.CallOption) (*FooResponse, error)
+	// Bar method demonstrates a simple call
+	Bar(ctx context.Context, in *BarRequest, opts ...grpc.CallOption) (*BarResponse, error)
+	// Baz method demonstrates a simple call
+	Baz(ctx context.Context, in *BazRequest, opts ...grpc.CallOption) (*BazResponse, error)
+}
+
+type exampleIndirectSelectionClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewdemoClient(cc grpc.ClientConnInterface) demoClient {
+	return &exampleIndirectSelectionClient{cc}
+}
+
+func (c *exampleIndirectSelectionClient) Foo(ctx context.Context, in *FooRequest, opts ...grpc.CallOption) (*FooResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FooResponse)
+	err := c.cc.Invoke(ctx, demo_Foo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exampleIndirectSelectionClient) Bar(ctx context.Context, in *BarRequest, opts ...grpc.CallOption) (*BarResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BarResponse)
+	err := c.cc.Invoke(ctx, demo_Bar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exampleIndirectSelectionClient) Baz(ctx context.Context, in *BazRequest, opts ...grpc.CallOption) (*BazResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BazResponse)
+	err := c.cc.Invoke(ctx, demo_Baz_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// demoServer is the server API for demo service.
+// All implementations must embed UnimplementeddemoServer
+// for forward compatibility.
+//
+// demo service demonstrates simple method calls
+type demoServer interface {
+	// Foo method demonstrates a simple call
+	Foo(context.Context, *FooRequest) (*FooResponse, error)
+	// Bar method demonstrates a simple call
+	Bar(context.Context, *BarRequest) (*BarResponse, error)
+	// Baz method demonstrates a simple call
+	Baz(context.Context, *BazRequest) (*BazResponse, error)
+	mustEmbedUnimplementeddemoServer()
+}
+
+// UnimplementeddemoServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementeddemoServer struct{}
+
+func (UnimplementeddemoServer) Foo(context.Context, *FooRequest) (*FooResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Foo not implemented")
+}
+func (UnimplementeddemoServer) Bar(context.Context, *BarRequest) (*BarResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Bar not implemented")
+}
+func (UnimplementeddemoServer) Baz(context.Context, *BazRequest) (*BazResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Baz not implemented")
+}
+func (UnimplementeddemoServer) mustEmbedUnimplementeddemoServer() {
+}
+func (UnimplementeddemoServer) testEmbeddedByValue() {}
+
+// UnsafedemoServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to demoServer will
+// result in compilation errors.
+type UnsafedemoServer interface {
+	mustEmbedUnimplementeddemoServer()
+}
+
+func RegisterdemoServer(s grpc.ServiceRegistrar, srv demoServer) {
+	// If the following call pancis, it indicates UnimplementeddemoServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&demo_ServiceDesc, srv)
+}
+
+func _demo_Foo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FooRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(demoServer).Foo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: demo_Foo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(demoServer).Foo(ctx, req.(*FooRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _demo_Bar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(demoServer).Bar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: demo_Bar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(demoServer).Bar(ctx, req.(*BarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _demo_Baz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BazRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(demoServer).Baz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: demo_Baz_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(demoServer).Baz(ctx, req.(*BazRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// demo_ServiceDesc is the grpc.ServiceDesc for demo service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var demo_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "demo.demo",
+	HandlerType: (*demoServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Foo",
+			Handler:    _demo_Foo_Handler,
+		},
+		{
+			MethodName: "Bar",
+			Handler:    _demo_Bar_Handler,
+		},
+		{
+			MethodName: "Baz",
+			Handler:    _demo_Baz_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service.proto",
+}
@@ -0,0 +1,68 @@
+package demo
+
+import (
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewLogger creates a new zap logger with the specified level and configuration.
+func NewLogger(level string) (logger *zap.Logger, err error) {
+	config := zap.NewProductionConfig()
+	config.OutputPaths = []string{"stdout"}
+	config.ErrorOutputPaths = []string{"stderr"}
+
+	// Configure log level
+	switch strings.ToLower(level) {
+	case "debug":
+		config.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	case "info":
+		config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+	case "warn":
+		config.Level = zap.NewAtomicLevelAt(zap.WarnLevel)
+	case "error":
+		config.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+	case "fatal":
+		config.Level = zap.NewAtomicLevelAt(zap.FatalLevel)
+	case "panic":
+		config.Level = zap.NewAtomicLevelAt(zap.PanicLevel)
+	default:
+		config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+	}
+
+	// Use JSON encoding for production-ready structured logs
+	config.Encoding = "json"
+	config.EncoderConfig = zapcore.EncoderConfig{
+		TimeKey:        "timestamp",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "message",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+
+	// Build logger
+	logger, err = config.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	// Replace global zap logger
+	zap.ReplaceGlobals(logger)
+
+	// Log startup information
+	logger.Info("Logger initialized",
+		zap.String("level", level),
+		zap.String("service", "demo"),
+		zap.Int("pid", os.Getpid()),
+	)
+
+	return logger, nil
+}
@@ -0,0 +1,433 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ConflictStyle controls how update records files where both the user and the templates made changes.
+type ConflictStyle string
+
+const (
+	// ConflictMarkers merges the new template content into the file, wrapping conflicting regions in markers.
+	ConflictMarkers ConflictStyle = "markers"
+	// ConflictRej leaves the file untouched, and writes a unified diff of the template changes to a .rej sidecar.
+	ConflictRej ConflictStyle = "rej"
+)
+
+// UpdateResult lists what an update did to each file, by path relative to the project root.
+type UpdateResult struct {
+	Added     []string
+	Updated   []string
+	Unchanged []string
+	Kept      []string
+	Deleted   []string
+	Merged    []string
+	Conflicts []string
+}
+
+// UpdateProject re-renders a previously generated project with the current embedded templates and merges the result
+// with any edits made since, against the original rendering kept with the manifest:
+//
+//   - files the user never touched are replaced with the new rendering
+//   - files whose template output hasn't changed keep the user's edits
+//   - files changed on both sides are merged three ways, and any overlapping changes are conflicts, recorded
+//     according to style
+//   - files the user deleted stay deleted
+//
+// Manifests written before boilerplate kept the original rendering only hold its hashes, so for those every file
+// changed on both sides is a conflict.
+func UpdateProject(outFs afero.Fs, projDir string, style ConflictStyle) (result UpdateResult, err error) {
+	return updateProject(outFs, projDir, nil, "", style)
+}
+
+// UpdateProjectFromFs is UpdateProject for projects generated from a template tree outside the binary, found at root
+// in templFs.
+func UpdateProjectFromFs(outFs afero.Fs, projDir string, templFs fs.FS, root string, style ConflictStyle) (result UpdateResult, err error) {
+	return updateProject(outFs, projDir, templFs, root, style)
+}
+
+func updateProject(outFs afero.Fs, projDir string, templFs fs.FS, root string, style ConflictStyle) (result UpdateResult, err error) {
+	if style != ConflictMarkers && style != ConflictRej {
+		err = fmt.Errorf("unknown conflict style %q", style)
+		return result, err
+	}
+
+	m, err := LoadManifest(outFs, projDir)
+	if err != nil {
+		return result, err
+	}
+
+	if templFs == nil {
+		if m.TemplateSource != "" {
+			err = fmt.Errorf("project was generated from the templates at %s, which must be supplied to update it", m.TemplateSource)
+			return result, err
+		}
+
+		if !IsValidProjectType(m.ProjectType) {
+			err = fmt.Errorf("manifest project type %q is not a valid project type", m.ProjectType)
+			return result, err
+		}
+	}
+
+	renderFs, renderRoot, err := renderForUpdate(m, templFs, root, m.valuesWithSecrets(func(string) string { return "" }))
+	if err != nil {
+		return result, err
+	}
+
+	rendered, err := readTree(renderFs, renderRoot)
+	if err != nil {
+		return result, err
+	}
+	rendered = withoutManifest(rendered)
+
+	// Secrets weren't kept, so render empty.  Files that use them can't be brought up to date, and are left alone.
+	secretFiles, err := filesUsingSecrets(m, templFs, root, rendered)
+	if err != nil {
+		return result, err
+	}
+
+	newManifest := Manifest{
+		ProjectType:    m.ProjectType,
+		TemplateSource: m.TemplateSource,
+		Version:        VERSION,
+		Values:         m.Values,
+		Secrets:        m.Secrets,
+		Files:          make(map[string]string, len(rendered)),
+		Features:       m.Features,
+	}
+
+	newBase := maps.Clone(rendered)
+	base, err := ReadBase(outFs, projDir)
+	if err != nil {
+		return result, err
+	}
+
+	// gen hands the project to gofmt once it is written, and so must update, to both renderings, or the formatting
+	// would look like a template change.
+	if slices.ContainsFunc(generationHooks(m, templFs, root), func(h Hook) bool { return h.Name == HookGofmt }) {
+		rendered, err = gofmtTree(rendered, m.Values)
+		if err != nil {
+			return result, err
+		}
+
+		if base != nil {
+			base, err = gofmtTree(base, m.Values)
+			if err != nil {
+				return result, err
+			}
+		}
+	}
+
+	for _, rel := range sortedKeys(rendered) {
+		newData := rendered[rel]
+		newHash := HashContent(newData)
+		newManifest.Files[rel] = newHash
+
+		path := filepath.Join(projDir, rel)
+		baseHash, tracked := m.Files[rel]
+
+		userData, readErr := afero.ReadFile(outFs, path)
+		if readErr != nil {
+			if !os.IsNotExist(readErr) {
+				err = errors.Wrapf(readErr, "failed to read %s", path)
+				return result, err
+			}
+
+			if tracked {
+				result.Deleted = append(result.Deleted, rel)
+				continue
+			}
+
+			err = writeFileAll(outFs, path, newData)
+			if err != nil {
+				return result, err
+			}
+
+			// New files take the mode they were generated with.  Existing ones keep whatever the user gave them.
+			info, statErr := renderFs.Stat(filepath.Join(renderRoot, rel))
+			if statErr == nil {
+				err = outFs.Chmod(path, info.Mode().Perm())
+				if err != nil {
+					err = errors.Wrapf(err, "failed to set mode of %s", path)
+					return result, err
+				}
+			}
+			result.Added = append(result.Added, rel)
+			continue
+		}
+
+		userHash := HashContent(userData)
+		baseData, inBase := base[rel]
+		switch {
+		case secretFiles[rel]:
+			newManifest.Files[rel] = baseHash
+			if !tracked {
+				delete(newManifest.Files, rel)
+			}
+			result.Kept = append(result.Kept, rel)
+
+		case userHash == newHash:
+			result.Unchanged = append(result.Unchanged, rel)
+
+		case inBase && bytes.Equal(baseData, newData):
+			// The templates render this file as they did, so whatever has become of it since stands, hooks and all.
+			if tracked && userHash == baseHash {
+				result.Unchanged = append(result.Unchanged, rel)
+			} else {
+				result.Kept = append(result.Kept, rel)
+			}
+
+		case tracked && userHash == baseHash:
+			// Untouched by the user, but boilerplate may have changed it since rendering, e.g. add patching in a
+			// feature.  That carries over where it merges cleanly.
+			if inBase && !isBinary(baseData) && !isBinary(userData) && !isBinary(newData) {
+				merged, clean := merge3(string(baseData), string(userData), string(newData), "")
+				if clean {
+					newData = []byte(merged)
+				}
+			}
+
+			err = writeFileAll(outFs, path, newData)
+			if err != nil {
+				return result, err
+			}
+			result.Updated = append(result.Updated, rel)
+
+		case tracked && newHash == baseHash:
+			result.Kept = append(result.Kept, rel)
+
+		case inBase && !isBinary(baseData) && !isBinary(userData) && !isBinary(newData):
+			merged, clean := merge3(string(baseData), string(userData), string(newData), fmt.Sprintf("boilerplate %s", VERSION))
+			if clean {
+				err = writeFileAll(outFs, path, []byte(merged))
+				if err != nil {
+					return result, err
+				}
+				result.Merged = append(result.Merged, rel)
+				continue
+			}
+
+			if style == ConflictRej {
+				diff := UnifiedDiff("a/"+rel, "b/"+rel, string(baseData), string(newData))
+				err = writeFileAll(outFs, path+".rej", []byte(diff))
+			} else {
+				err = writeFileAll(outFs, path, []byte(merged))
+			}
+			if err != nil {
+				return result, err
+			}
+			result.Conflicts = append(result.Conflicts, rel)
+
+		default:
+			err = writeConflict(outFs, path, rel, userData, newData, style)
+			if err != nil {
+				return result, err
+			}
+			result.Conflicts = append(result.Conflicts, rel)
+		}
+	}
+
+	err = newManifest.Write(outFs, projDir)
+	if err != nil {
+		return result, err
+	}
+
+	err = WriteBase(outFs, projDir, newBase)
+	return result, err
+}
+
+// renderForUpdate renders the project recorded in m, from templFs at root if given, or else the embedded templates,
+// with vals.  It returns the file system rendered into, and the project root within it.
+func renderForUpdate(m Manifest, templFs fs.FS, root string, vals map[string]any) (renderFs afero.Fs, renderRoot string, err error) {
+	renderFs = afero.NewMemMapFs()
+	var w TmplWriter
+	if templFs == nil {
+		w, err = NewTmplWriter(renderFs, m.ProjectType, vals)
+	} else {
+		w, err = NewTmplWriterFromFs(renderFs, templFs, root, m.ProjectType, vals)
+		w.Source = m.TemplateSource
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "failed to create template writer")
+		return renderFs, renderRoot, err
+	}
+
+	err = w.BuildProject("/")
+	if err != nil {
+		err = errors.Wrapf(err, "failed to render project")
+		return renderFs, renderRoot, err
+	}
+
+	return renderFs, filepath.Join("/", w.ProjectRoot()), err
+}
+
+// filesUsingSecrets finds the files among rendered whose content depends on the secrets m left out, by rendering
+// again with a placeholder for each.
+func filesUsingSecrets(m Manifest, templFs fs.FS, root string, rendered map[string][]byte) (files map[string]bool, err error) {
+	files = make(map[string]bool)
+	if len(m.Secrets) == 0 {
+		return files, err
+	}
+
+	vals := m.valuesWithSecrets(func(key string) string { return "boilerplate-secret-" + key })
+	renderFs, renderRoot, err := renderForUpdate(m, templFs, root, vals)
+	if err != nil {
+		return files, err
+	}
+
+	withSecrets, err := readTree(renderFs, renderRoot)
+	if err != nil {
+		return files, err
+	}
+
+	for rel, data := range rendered {
+		if !bytes.Equal(data, withSecrets[rel]) {
+			files[rel] = true
+		}
+	}
+
+	return files, err
+}
+
+// valuesWithSecrets returns the manifest's values, with each secret left out of them set to secret(key).
+func (m Manifest) valuesWithSecrets(secret func(key string) string) map[string]any {
+	if len(m.Secrets) == 0 {
+		return m.Values
+	}
+
+	vals := make(map[string]any, len(m.Values)+len(m.Secrets))
+	for k, v := range m.Values {
+		vals[k] = v
+	}
+	for _, key := range m.Secrets {
+		vals[key] = secret(key)
+	}
+
+	return vals
+}
+
+// generationHooks returns the hooks gen ran for a project: those of its template spec, if it came from templates
+// with one, or else those of its type.
+func generationHooks(m Manifest, templFs fs.FS, root string) []Hook {
+	if templFs != nil {
+		spec, err := LoadTemplateSpec(templFs, root)
+		if err == nil {
+			return ProjectTypeFromSpec(spec, templFs, root).Hooks
+		}
+	}
+
+	pt, _ := LookupProjectType(m.ProjectType)
+	return pt.Hooks
+}
+
+// gofmtTree formats the Go files among files, keyed by path, as the gofmt hook would on disk.
+func gofmtTree(files map[string][]byte, vals map[string]any) (formatted map[string][]byte, err error) {
+	afs := afero.NewMemMapFs()
+	for rel, data := range files {
+		err = writeFileAll(afs, filepath.Join("/", rel), data)
+		if err != nil {
+			return formatted, err
+		}
+	}
+
+	err = GofmtHook().Run(context.Background(), afs, "/", vals)
+	if err != nil && !errors.Is(err, ErrHookSkipped) {
+		err = errors.Wrapf(err, "failed to format rendered project")
+		return formatted, err
+	}
+
+	return readTree(afs, "/")
+}
+
+// writeConflict records a file changed both by the user and by the templates.  Binary files always get a sidecar
+// holding the new content, since markers would corrupt them.
+func writeConflict(outFs afero.Fs, path, rel string, userData, newData []byte, style ConflictStyle) error {
+	if isBinary(userData) || isBinary(newData) {
+		return writeFileAll(outFs, path+".rej", newData)
+	}
+
+	if style == ConflictRej {
+		diff := UnifiedDiff("a/"+rel, "b/"+rel, string(userData), string(newData))
+		return writeFileAll(outFs, path+".rej", []byte(diff))
+	}
+
+	merged := mergeWithMarkers(string(userData), string(newData), fmt.Sprintf("boilerplate %s", VERSION))
+	return writeFileAll(outFs, path, []byte(merged))
+}
+
+// binarySniffLen is how much of a file isBinary looks at.
+const binarySniffLen = 8000
+
+// isBinary reports whether data looks like binary content rather than text.
+func isBinary(data []byte) bool {
+	if len(data) > binarySniffLen {
+		data = data[:binarySniffLen]
+	}
+
+	return bytes.IndexByte(data, 0) != -1
+}
+
+// readTree reads every file below root into a map keyed by path relative to root.
+func readTree(afs afero.Fs, root string) (files map[string][]byte, err error) {
+	files = make(map[string][]byte)
+	err = afero.Walk(afs, root, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		data, readErr := afero.ReadFile(afs, path)
+		if readErr != nil {
+			return readErr
+		}
+
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return relErr
+		}
+
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		err = errors.Wrapf(err, "failed to read tree at %s", root)
+		return files, err
+	}
+
+	return files, err
+}
+
+func writeFileAll(afs afero.Fs, path string, data []byte) error {
+	err := afs.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create directory for %s", path)
+	}
+
+	err = afero.WriteFile(afs, path, data, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateProject(t *testing.T) {
+	afs := afero.NewMemMapFs()
+	params := MapOnly((&CobraCliToolParams{
+		ProjectName:      "test-proj",
+		ProjectPackage:   "github.com/test/test-proj",
+		ProjectShortDesc: "proj short",
+		ProjectLongDesc:  "proj long",
+		MaintainerName:   "test",
+		MaintainerEmail:  "test@example.com",
+		DbtRepo:          "https://dbt",
+		ProjectVersion:   "0.1.0",
+		GolangVersion:    "1.22",
+	}).AsMap())
+
+	w, err := NewTmplWriter(afs, CobraProjectType, params)
+	require.NoError(t, err)
+	require.NoError(t, w.BuildProject("/out"))
+
+	// As gen leaves it, formatted.
+	projDir := "/out/test-proj"
+	require.NoError(t, GofmtHook().Run(context.Background(), afs, projDir, params))
+	require.NoError(t, rehashManifest(afs, projDir, nil))
+
+	m, err := LoadManifest(afs, projDir)
+	require.NoError(t, err)
+	assert.Equal(t, CobraProjectType, m.ProjectType)
+	assert.Equal(t, "test-proj", m.Values["ProjectName"])
+	require.Contains(t, m.Files, "go.mod")
+
+	base, err := ReadBase(afs, projDir)
+	require.NoError(t, err)
+	require.Contains(t, base, "go.mod")
+
+	// The base is kept as plain files beside the manifest, not in it.
+	manifest, err := afero.ReadFile(afs, filepath.Join(projDir, ManifestFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(manifest), `"base"`)
+	rendered, err := afero.ReadFile(afs, filepath.Join(projDir, BaseDir, "go.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, base["go.mod"], rendered)
+	assert.NotContains(t, m.Files, BaseDir+"/go.mod")
+
+	write := func(rel, content string) {
+		require.NoError(t, afero.WriteFile(afs, filepath.Join(projDir, rel), []byte(content), 0644))
+	}
+
+	// Untouched by the user, but rendered by an older template.
+	write("LICENSE", "old license\n")
+	m.Files["LICENSE"] = HashContent([]byte("old license\n"))
+	base["LICENSE"] = []byte("old license\n")
+
+	// Edited by the user, template output unchanged.
+	write("README.md", "my readme\n")
+
+	// Edited by the user, and rendered by an older template.
+	write("main.go", "package main\n// mine\n")
+	m.Files["main.go"] = HashContent([]byte("package main\n// old\n"))
+	base["main.go"] = []byte("package main\n// old\n")
+
+	// Edited by the user at the bottom, and rendered by an older template that differed at the top.
+	rootGo, err := afero.ReadFile(afs, filepath.Join(projDir, "cmd/root.go"))
+	require.NoError(t, err)
+	oldRootGo := strings.Replace(string(rootGo), "package cmd\n", "package cmd // old\n", 1)
+	write("cmd/root.go", oldRootGo+"// mine\n")
+	m.Files["cmd/root.go"] = HashContent([]byte(oldRootGo))
+	base["cmd/root.go"] = []byte(oldRootGo)
+
+	// Deleted by the user.
+	require.NoError(t, afs.Remove(filepath.Join(projDir, "metadata.json")))
+
+	// Added to the templates since generation.
+	require.NoError(t, afs.Remove(filepath.Join(projDir, "go.mod")))
+	delete(m.Files, "go.mod")
+	delete(base, "go.mod")
+
+	require.NoError(t, WriteBase(afs, projDir, base))
+	require.NoError(t, m.Write(afs, projDir))
+
+	result, err := UpdateProject(afs, projDir, ConflictMarkers)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"LICENSE"}, result.Updated)
+	assert.Equal(t, []string{"README.md"}, result.Kept)
+	assert.Equal(t, []string{"main.go"}, result.Conflicts)
+	assert.Equal(t, []string{"metadata.json"}, result.Deleted)
+	assert.Equal(t, []string{"go.mod"}, result.Added)
+	assert.Equal(t, []string{"cmd/root.go"}, result.Merged)
+
+	// Both edits made it in, without markers.
+	merged, err := afero.ReadFile(afs, filepath.Join(projDir, "cmd/root.go"))
+	require.NoError(t, err)
+	assert.Equal(t, string(rootGo)+"// mine\n", string(merged))
+
+	mainGo, err := afero.ReadFile(afs, filepath.Join(projDir, "main.go"))
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(mainGo), "<<<<<<< yours\n// mine\n=======\n"), "conflict markers missing: %s", mainGo)
+
+	license, err := afero.ReadFile(afs, filepath.Join(projDir, "LICENSE"))
+	require.NoError(t, err)
+	assert.NotEqual(t, "old license\n", string(license))
+
+	// A second update finds nothing left to do but the unresolved conflict, which is now the user's edit.
+	result, err = UpdateProject(afs, projDir, ConflictRej)
+	require.NoError(t, err)
+	assert.Empty(t, result.Conflicts)
+	assert.Contains(t, result.Kept, "main.go")
+}
+
+// TestUpdateProject_AfterHooks checks that a project straight out of gen, hooks and all, is up to date: nothing the
+// hooks reformatted is taken for the user's edits.
+func TestUpdateProject_AfterHooks(t *testing.T) {
+	for _, pt := range builtinProjectTypes() {
+		t.Run(pt.Name, func(t *testing.T) {
+			afs := afero.NewMemMapFs()
+			res, err := Generate(context.Background(), Options{Type: pt.Name, Values: goldenAnswers(), OutFs: afs, Dest: "/out", Hooks: true})
+			require.NoError(t, err)
+
+			result, err := UpdateProject(afs, res.ProjectDir, ConflictMarkers)
+			require.NoError(t, err)
+			assert.Empty(t, result.Kept)
+			assert.Empty(t, result.Conflicts)
+			assert.Empty(t, result.Updated)
+		})
+	}
+}
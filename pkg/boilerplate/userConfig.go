@@ -0,0 +1,254 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// UserConfigEnv names the environment variable that overrides where the user config file lives.
+const UserConfigEnv = "BOILERPLATE_CONFIG"
+
+// userDefaultEnvPrefix starts the environment variables that supply defaults, e.g. BOILERPLATE_MAINTAINER_NAME.
+const userDefaultEnvPrefix = "BOILERPLATE_"
+
+// Where a user default came from.
+const (
+	SourceGitConfig  = "git config"
+	SourceUserConfig = "config file"
+	SourceEnv        = "environment"
+)
+
+// UserDefault is the user's default for one prompt, and where it came from.
+type UserDefault struct {
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// UserDefaults are per-user and per-org defaults for prompts, keyed by prompt key.  They take the place of a prompt's
+// own default, and like them may be templates over earlier answers, e.g. github.com/myorg/{{.ProjectName}}.
+type UserDefaults map[string]UserDefault
+
+var userConfigKey = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`) //nolint:gochecknoglobals // compiled once
+
+// gitDefaults are the prompts filled from git config when nothing else sets them.  The owner follows the maintainer.
+var gitDefaults = map[ParamPrompt]string{ //nolint:gochecknoglobals // fixed mapping
+	ProjMaintainerName:  "user.name",
+	ProjMaintainerEmail: "user.email",
+}
+
+// UserConfigPath returns where the user config file lives: $BOILERPLATE_CONFIG if set, or else
+// boilerplate/config.yaml under $XDG_CONFIG_HOME or ~/.config.
+func UserConfigPath() (path string, err error) {
+	if path = os.Getenv(UserConfigEnv); path != "" {
+		return path, err
+	}
+
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return path, fmt.Errorf("failed to find home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(dir, "boilerplate", "config.yaml"), err
+}
+
+// LoadUserConfig reads the defaults in a user config file, a flat YAML map of prompt keys to values.  A missing file
+// holds no defaults.
+func LoadUserConfig(path string) (vals map[string]string, err error) {
+	vals, err = LoadValuesFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return make(map[string]string), nil
+	}
+
+	return vals, err
+}
+
+// SaveUserConfig writes defaults to a user config file, creating its directory if need be.
+func SaveUserConfig(path string, vals map[string]string) (err error) {
+	data, err := yaml.Marshal(vals)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user config: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	err = os.WriteFile(path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return err
+}
+
+// SetUserConfigValue sets one default in the user config file at path, or removes it if val is empty.
+func SetUserConfigValue(path string, key string, val string) (err error) {
+	if !userConfigKey.MatchString(key) {
+		return fmt.Errorf("invalid key %q: expected a prompt key such as %s", key, ProjMaintainerName)
+	}
+
+	vals, err := LoadUserConfig(path)
+	if err != nil {
+		return err
+	}
+
+	if val == "" {
+		delete(vals, key)
+	} else {
+		vals[key] = val
+	}
+
+	return SaveUserConfig(path, vals)
+}
+
+// LoadUserDefaults gathers the user's defaults.  The user config file wins over git config, and BOILERPLATE_*
+// environment variables, named for the prompt key in SCREAMING_SNAKE case, win over both.  Like every default, a
+// variable only applies to a prompt it names, in whatever project type is generated, so others that happen to share
+// the prefix are never taken for answers.
+func LoadUserDefaults() (defs UserDefaults, err error) {
+	defs = make(UserDefaults)
+
+	for key, name := range gitDefaults {
+		if val := gitConfigValue(name); val != "" {
+			defs[key.String()] = UserDefault{Value: val, Source: SourceGitConfig}
+		}
+	}
+
+	path, err := UserConfigPath()
+	if err != nil {
+		return defs, err
+	}
+
+	vals, err := LoadUserConfig(path)
+	if err != nil {
+		return defs, err
+	}
+
+	for k, v := range vals {
+		defs.set(k, UserDefault{Value: v, Source: SourceUserConfig})
+	}
+
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		name, ok := strings.CutPrefix(k, userDefaultEnvPrefix)
+		if !ok || k == UserConfigEnv || name == "" || v == "" {
+			continue
+		}
+		defs.set(name, UserDefault{Value: v, Source: SourceEnv})
+	}
+
+	return defs, err
+}
+
+// Lookup finds the default for a prompt.  Keys match regardless of case and underscores, so MAINTAINER_NAME is the
+// default for MaintainerName.
+func (d UserDefaults) Lookup(key ParamPrompt) (def UserDefault, ok bool) {
+	if def, ok = d[key.String()]; ok {
+		return def, ok
+	}
+
+	want := userDefaultName(key.String())
+	for k, v := range d {
+		if userDefaultName(k) == want {
+			return v, true
+		}
+	}
+
+	return def, false
+}
+
+// Keys lists the keys with defaults, sorted.
+func (d UserDefaults) Keys() (keys []string) {
+	for k := range d {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// set records a default, replacing any under a key of the same name.
+func (d UserDefaults) set(key string, def UserDefault) {
+	name := userDefaultName(key)
+	for k := range d {
+		if userDefaultName(k) == name {
+			delete(d, k)
+		}
+	}
+
+	d[key] = def
+}
+
+// userDefaultName folds a key to the name of its environment variable.
+func userDefaultName(key string) string {
+	return strings.ToUpper(strings.Join(lowerWords(key), "_"))
+}
+
+// Values returns the value of each default, by key, e.g. for Options.Defaults.
+func (d UserDefaults) Values() (vals map[string]string) {
+	vals = make(map[string]string, len(d))
+	for k, v := range d {
+		vals[k] = v.Value
+	}
+
+	return vals
+}
+
+// withDefaults returns prompts with the defaults given, matched to keys as Lookup does, in place of their own.
+func withDefaults(prompts map[ParamPrompt]Prompt, defaults map[string]string) map[ParamPrompt]Prompt {
+	if len(defaults) == 0 {
+		return prompts
+	}
+
+	defs := make(UserDefaults, len(defaults))
+	for k, v := range defaults {
+		defs[k] = UserDefault{Value: v}
+	}
+
+	out := make(map[ParamPrompt]Prompt, len(prompts))
+	for key, p := range prompts {
+		if def, ok := defs.Lookup(key); ok {
+			p.DefaultValue = def.Value
+		}
+		out[key] = p
+	}
+
+	return out
+}
+
+// gitConfigValue reads a git config setting, or nothing if git or the setting is missing.
+func gitConfigValue(name string) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		return ""
+	}
+
+	out, err := exec.Command("git", "config", "--get", name).Output()
+	if err != nil {
+		return ""
+	}
+
+	return string(bytes.TrimSpace(out))
+}
@@ -0,0 +1,99 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "boilerplate", "config.yaml")
+
+	vals, err := LoadUserConfig(path)
+	require.NoError(t, err)
+	assert.Empty(t, vals)
+
+	require.NoError(t, SetUserConfigValue(path, "DbtRepo", "https://dbt.example.com"))
+	require.NoError(t, SetUserConfigValue(path, "ProjectPackage", "github.com/myorg/{{.ProjectName}}"))
+	require.NoError(t, SetUserConfigValue(path, "ProjectPackage", ""))
+	assert.Error(t, SetUserConfigValue(path, "not a key", "x"))
+
+	vals, err = LoadUserConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"DbtRepo": "https://dbt.example.com"}, vals)
+
+	t.Setenv(UserConfigEnv, path)
+	t.Setenv("BOILERPLATE_DBT_REPO", "https://env.example.com")
+	t.Setenv("BOILERPLATE_GOLANG_VERSION", "1.22")
+	t.Setenv("BOILERPLATE_NOT_A_PROMPT", "x")
+
+	defs, err := LoadUserDefaults()
+	require.NoError(t, err)
+
+	def, ok := defs.Lookup(DbtRepo)
+	require.True(t, ok)
+	assert.Equal(t, UserDefault{Value: "https://env.example.com", Source: SourceEnv}, def)
+
+	def, ok = defs.Lookup(GoVersion)
+	require.True(t, ok)
+	assert.Equal(t, "1.22", def.Value)
+
+	_, ok = defs.Lookup(DockerProject)
+	assert.False(t, ok)
+
+	// A variable is only an answer to a prompt it names, in whichever type is generated, template trees included.
+	t.Setenv("BOILERPLATE_REGION", "eu-west-1")
+	defs, err = LoadUserDefaults()
+	require.NoError(t, err)
+
+	templFs := fstest.MapFS{
+		TemplateSpecFileName:          {Data: []byte("name: regional\nprompts: [{key: ProjectName}, {key: Region}]\n")},
+		"{{.ProjectName}}/region.txt": {Data: []byte("{{.Region}}\n")},
+	}
+	res, err := Generate(context.Background(), Options{Templates: templFs, Values: map[string]string{"ProjectName": "x"}, Defaults: defs.Values(), OutFs: afero.NewMemMapFs()})
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1", res.Values["Region"])
+	assert.NotContains(t, res.Values, "NotAPrompt")
+	assert.NotContains(t, res.Values, "NOT_A_PROMPT")
+}
+
+func TestUserDefaults_SeedPrompts(t *testing.T) {
+	defs := UserDefaults{
+		"ProjectPackage":  {Value: "github.com/myorg/{{.ProjectName}}", Source: SourceUserConfig},
+		"MAINTAINER_NAME": {Value: "Org Bot", Source: SourceEnv},
+	}
+
+	pt, ok := LookupProjectType(HeadlessServiceType)
+	require.True(t, ok)
+	vals := map[string]string{
+		"ProjectName":     "svc",
+		"DbtRepo":         "https://dbt",
+		"MaintainerEmail": "bot@myorg.com",
+	}
+	data, err := pt.AskParams(vals, defs.Values(), nil)
+	require.NoError(t, err)
+
+	dataMap, err := data.AsMap()
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/myorg/svc", dataMap[ProjPkgName.String()])
+	assert.Equal(t, "Org Bot", dataMap[ProjMaintainerName.String()])
+	assert.Equal(t, "Org Bot", dataMap[OwnerName.String()])
+
+	// They only apply where given, so without them the maintainer has no default.
+	_, err = pt.AskParams(vals, nil, nil)
+	assert.ErrorContains(t, err, "MaintainerName: no value supplied and no default available")
+}
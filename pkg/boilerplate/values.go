@@ -0,0 +1,203 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strings"
+)
+
+// ValidationError describes a single answer that failed validation.
+type ValidationError struct {
+	Key ParamPrompt
+	Msg string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Msg)
+}
+
+// ValidationErrors collects every invalid answer so they can be reported at once.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, ve := range e {
+		msgs = append(msgs, ve.Error())
+	}
+
+	return fmt.Sprintf("invalid values:\n  %s", strings.Join(msgs, "\n  "))
+}
+
+// Unwrap lets errors.As find the first ValidationError.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, ve := range e {
+		errs = append(errs, ve)
+	}
+
+	return errs
+}
+
+// LoadValuesFile reads a YAML or JSON answers file into a flat map of prompt keys to values.
+func LoadValuesFile(path string) (vals map[string]string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to read values file %s", path)
+		return vals, err
+	}
+
+	raw := make(map[string]any)
+	// JSON is a subset of YAML, so one decoder covers both formats.
+	err = yaml.Unmarshal(data, &raw)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to parse values file %s", path)
+		return vals, err
+	}
+
+	return FlattenValues(raw, path)
+}
+
+// FlattenValues turns decoded YAML or JSON answers into strings, as the prompts take them.  Scalars are formatted as
+// is, and lists, answering multi-choice questions, are joined with commas.  source names where they came from, for
+// errors.
+func FlattenValues(raw map[string]any, source string) (vals map[string]string, err error) {
+	vals = make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch v := v.(type) {
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				if _, isMap := item.(map[string]any); isMap {
+					err = fmt.Errorf("value for %q in %s must be a scalar or a list of them", k, source)
+					return vals, err
+				}
+				items = append(items, fmt.Sprint(item))
+			}
+			vals[k] = strings.Join(items, ",")
+		case map[string]any:
+			err = fmt.Errorf("value for %q in %s must be a scalar or a list of them", k, source)
+			return vals, err
+		case nil:
+			vals[k] = ""
+		default:
+			vals[k] = fmt.Sprint(v)
+		}
+	}
+
+	return vals, err
+}
+
+// ParseSetValues parses repeated Key=Value overrides into a map.
+func ParseSetValues(sets []string) (vals map[string]string, err error) {
+	vals = make(map[string]string, len(sets))
+	for _, s := range sets {
+		k, v, ok := strings.Cut(s, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			err = fmt.Errorf("invalid value override %q: expected Key=Value", s)
+			return vals, err
+		}
+
+		vals[k] = v
+	}
+
+	return vals, err
+}
+
+// MergeValues merges value maps left to right, later maps winning.
+func MergeValues(maps ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, m := range maps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
+
+// validateAnswer checks that a value is of the prompt's kind and passes its validations, returning it in canonical
+// form, e.g. "yes" as "true", or else the first failure message.
+func validateAnswer(p Prompt, val string) (answer string, msg string, ok bool) {
+	answer, msg, ok = p.parseAnswer(val)
+	if !ok {
+		return answer, msg, ok
+	}
+
+	for _, v := range p.Validations {
+		if !v.IsValid(answer) {
+			return answer, v.InvalidMsg, false
+		}
+	}
+
+	return answer, msg, ok
+}
+
+// paramsFromValues copies supplied answers into pvals and validates them.  When useDefaults is set, every prompt
+// without an answer falls back to its default, and prompts that remain empty are reported as missing.
+func paramsFromValues(vals map[string]string, prompts map[ParamPrompt]Prompt, pvals PromptValues, useDefaults bool) (err error) {
+	values := pvals.Values()
+	var verrs ValidationErrors
+
+	for _, k := range sortedKeys(vals) {
+		key := ParamPrompt(k)
+		dataVar, ok := values[key]
+		if !ok || dataVar == nil {
+			verrs = append(verrs, ValidationError{Key: key, Msg: "not a value supported by this project type"})
+			continue
+		}
+
+		*dataVar = vals[k]
+
+		if *dataVar == "" {
+			continue
+		}
+
+		answer, msg, valid := validateAnswer(prompts[key], *dataVar)
+		if !valid {
+			verrs = append(verrs, ValidationError{Key: key, Msg: fmt.Sprintf("%s input: %q", msg, *dataVar)})
+			continue
+		}
+		*dataVar = answer
+	}
+
+	if useDefaults {
+		for _, key := range promptOrderFor(pvals, prompts) {
+			p := prompts[key]
+			dataVar := values[key]
+			if dataVar == nil || *dataVar != "" || !promptApplies(p, values) {
+				continue
+			}
+
+			*dataVar = promptDefault(p, values)
+			if *dataVar == "" {
+				verrs = append(verrs, ValidationError{Key: key, Msg: "no value supplied and no default available"})
+				continue
+			}
+
+			answer, msg, valid := validateAnswer(p, *dataVar)
+			if !valid {
+				verrs = append(verrs, ValidationError{Key: key, Msg: fmt.Sprintf("%s default: %q", msg, *dataVar)})
+				continue
+			}
+			*dataVar = answer
+		}
+	}
+
+	if len(verrs) > 0 {
+		return verrs
+	}
+
+	return err
+}
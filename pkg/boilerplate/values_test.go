@@ -0,0 +1,113 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadValuesFile(t *testing.T) {
+	for _, tc := range []struct {
+		Name    string
+		Content string
+		Want    map[string]string
+		WantErr bool
+	}{
+		{
+			Name:    "YAML",
+			Content: "ProjectName: foo\nDefaultServerPort: 8080\n",
+			Want:    map[string]string{"ProjectName": "foo", "DefaultServerPort": "8080"},
+		},
+		{
+			Name:    "JSON",
+			Content: `{"ProjectName": "foo", "ProjectVersion": "1.2.3"}`,
+			Want:    map[string]string{"ProjectName": "foo", "ProjectVersion": "1.2.3"},
+		},
+		{
+			Name:    "Lists answer multi-choice questions",
+			Content: "Features: [auth, metrics]\nEnableAuth: true\n",
+			Want:    map[string]string{"Features": "auth,metrics", "EnableAuth": "true"},
+		},
+		{
+			Name:    "Nested values rejected",
+			Content: "ProjectName:\n  nested: true\n",
+			WantErr: true,
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "values")
+			require.NoError(t, os.WriteFile(path, []byte(tc.Content), 0600))
+
+			vals, err := LoadValuesFile(path)
+			if tc.WantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.Want, vals)
+		})
+	}
+}
+
+func TestParseSetValues(t *testing.T) {
+	vals, err := ParseSetValues([]string{"ProjectName=foo", "ProjectLongDesc=a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ProjectName": "foo", "ProjectLongDesc": "a=b"}, vals)
+
+	_, err = ParseSetValues([]string{"ProjectName"})
+	assert.Error(t, err)
+}
+
+func TestParamsForProject_NoPrompt(t *testing.T) {
+	data, err := ParamsForProject(HeadlessServiceType, map[string]string{
+		"ProjectName":     "test-svc",
+		"DbtRepo":         "https://dbt",
+		"MaintainerName":  "Tester",
+		"MaintainerEmail": "tester@foo.com",
+	}, true)
+	require.NoError(t, err)
+
+	dataMap, err := data.AsMap()
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-svc", dataMap[ProjName.String()])
+	assert.Equal(t, "github.com/something/test-svc", dataMap[ProjPkgName.String()])
+	assert.Equal(t, "8080", dataMap[ServerDefPort.String()])
+	assert.Equal(t, "boilerplate autogen project", dataMap[ServerShortDesc.String()])
+	assert.Equal(t, "tester@foo.com", dataMap[OwnerEmail.String()])
+}
+
+func TestParamsForProject_NoPromptInvalid(t *testing.T) {
+	_, err := ParamsForProject(CobraProjectType, map[string]string{
+		"ProjectName":     "bad name",
+		"MaintainerEmail": "not-an-email",
+		"EnvPrefix":       "FOO",
+	}, true)
+	require.Error(t, err)
+
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+
+	keys := make([]ParamPrompt, 0, len(verrs))
+	for _, ve := range verrs {
+		keys = append(keys, ve.Key)
+	}
+
+	assert.Contains(t, keys, ProjName)
+	assert.Contains(t, keys, ProjMaintainerEmail)
+	assert.Contains(t, keys, ProjEnvPrefix, "cobra projects have no env prefix")
+}
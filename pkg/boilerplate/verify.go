@@ -0,0 +1,477 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"go/ast"
+	"go/build/constraint"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"golang.org/x/mod/modfile"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// VerifyProblem is something wrong at a place in a file.  Line is 0 when it concerns the whole file.
+type VerifyProblem struct {
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+	Msg  string `json:"msg"`
+}
+
+func (p VerifyProblem) String() string {
+	switch {
+	case p.File == "":
+		return p.Msg
+	case p.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Msg)
+	default:
+		return fmt.Sprintf("%s: %s", p.File, p.Msg)
+	}
+}
+
+// VerifyError lists every problem VerifyProject found.
+type VerifyError struct {
+	Problems []VerifyProblem
+}
+
+func (e *VerifyError) Error() string {
+	lines := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		lines = append(lines, p.String())
+	}
+
+	return fmt.Sprintf("generated Go code has %d problem(s):\n\t%s", len(e.Problems), strings.Join(lines, "\n\t"))
+}
+
+// errUnresolved is returned for imports VerifyProject can't load, i.e. those outside the project and the standard
+// library.  Packages using them are still type checked, less what comes from those imports.
+var errUnresolved = errors.New("import not resolvable")
+
+// stdImporter type checks standard library packages from source.  It is shared, as that's slow.
+var stdImporter = struct { //nolint:gochecknoglobals // shared cache of standard library packages
+	sync.Mutex
+	types.Importer
+}{Importer: importer.ForCompiler(token.NewFileSet(), "source", nil)}
+
+// verifyModule is a go.mod found in the project.
+type verifyModule struct {
+	dir  string
+	path string
+	reqs []string
+}
+
+// verifyPackage is a directory of Go files in the project.
+type verifyPackage struct {
+	dir     string
+	files   []*ast.File
+	checked *types.Package
+	err     error
+	busy    bool
+}
+
+// verifier checks a generated project.  Paths are relative to the project root.
+type verifier struct {
+	afs      afero.Fs
+	projDir  string
+	fset     *token.FileSet
+	modules  []verifyModule
+	pkgs     map[string]*verifyPackage
+	problems []VerifyProblem
+}
+
+// VerifyProject checks the Go code in a generated project: every .go file must parse, imports of the project's own
+// packages must exist, other imports must come from the standard library or a required module, and packages must
+// type check as far as their imports can be resolved.  If modulePath is set, the root go.mod must declare it.
+// Problems are returned as a *VerifyError.
+func VerifyProject(afs afero.Fs, projDir string, modulePath string) error {
+	v := newVerifier(afs, projDir)
+
+	files, err := v.findFiles()
+	if err != nil {
+		return err
+	}
+
+	if modulePath != "" {
+		v.checkModulePath(modulePath)
+	}
+
+	byDir := make(map[string][]*ast.File)
+	var dirs []string
+	for _, rel := range files {
+		f := v.parse(rel)
+		if f == nil {
+			continue
+		}
+
+		v.checkImports(rel, f)
+
+		if strings.HasSuffix(rel, "_test.go") || !buildable(f) {
+			continue
+		}
+
+		dir := path.Dir(rel)
+		if _, seen := byDir[dir]; !seen {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], f)
+	}
+
+	for _, dir := range dirs {
+		if importPath, ok := v.importPath(dir); ok {
+			v.pkgs[importPath] = &verifyPackage{dir: dir, files: byDir[dir]}
+		}
+	}
+
+	for _, dir := range dirs {
+		importPath, ok := v.importPath(dir)
+		if !ok {
+			continue
+		}
+		_, _ = v.check(importPath)
+	}
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		if v.problems[i].File != v.problems[j].File {
+			return v.problems[i].File < v.problems[j].File
+		}
+		return v.problems[i].Line < v.problems[j].Line
+	})
+
+	return &VerifyError{Problems: v.problems}
+}
+
+// checkProjectImports parses the Go files in a generated project and checks their imports, without type checking.
+func checkProjectImports(afs afero.Fs, projDir string) (problems []VerifyProblem, err error) {
+	v := newVerifier(afs, projDir)
+
+	files, err := v.findFiles()
+	if err != nil {
+		return problems, err
+	}
+
+	for _, rel := range files {
+		if f := v.parse(rel); f != nil {
+			v.checkImports(rel, f)
+		}
+	}
+
+	return v.problems, err
+}
+
+func newVerifier(afs afero.Fs, projDir string) *verifier {
+	return &verifier{
+		afs:     afs,
+		projDir: projDir,
+		fset:    token.NewFileSet(),
+		pkgs:    make(map[string]*verifyPackage),
+	}
+}
+
+// findFiles lists the project's .go files and loads its go.mod files, skipping the directories the go tool ignores.
+func (v *verifier) findFiles() (files []string, err error) {
+	err = afero.Walk(v.afs, v.projDir, func(p string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		rel, relErr := filepath.Rel(v.projDir, p)
+		if relErr != nil {
+			return relErr
+		}
+		rel = filepath.ToSlash(rel)
+
+		name := info.Name()
+		if info.IsDir() {
+			if rel != "." && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case name == "go.mod":
+			v.loadModule(p, path.Dir(rel))
+		case strings.HasSuffix(name, ".go"):
+			files = append(files, rel)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return files, fmt.Errorf("failed to read generated project at %s: %w", v.projDir, err)
+	}
+
+	// Longest first, so the first match is the nearest module.
+	sort.Slice(v.modules, func(i, j int) bool { return len(v.modules[i].dir) > len(v.modules[j].dir) })
+
+	return files, err
+}
+
+func (v *verifier) problem(file string, line int, format string, args ...any) {
+	v.problems = append(v.problems, VerifyProblem{File: file, Line: line, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (v *verifier) loadModule(p, dir string) {
+	rel := path.Join(dir, "go.mod")
+
+	data, err := afero.ReadFile(v.afs, p)
+	if err != nil {
+		v.problem(rel, 0, "%v", err)
+		return
+	}
+
+	mf, err := modfile.ParseLax(rel, data, nil)
+	if err != nil {
+		v.problem(rel, 0, "%v", err)
+		return
+	}
+
+	if mf.Module == nil {
+		v.problem(rel, 0, "no module directive")
+		return
+	}
+
+	mod := verifyModule{dir: dir, path: mf.Module.Mod.Path}
+	for _, r := range mf.Require {
+		mod.reqs = append(mod.reqs, r.Mod.Path)
+	}
+	v.modules = append(v.modules, mod)
+}
+
+// checkModulePath compares the root module with the one the project was generated for.
+func (v *verifier) checkModulePath(modulePath string) {
+	for _, m := range v.modules {
+		if m.dir != "." {
+			continue
+		}
+
+		if m.path != modulePath {
+			v.problem("go.mod", 1, "module is %s, but the project package is %s", m.path, modulePath)
+		}
+		return
+	}
+
+	v.problem("go.mod", 0, "missing, but the project package is %s", modulePath)
+}
+
+// module returns the module containing dir.
+func (v *verifier) module(dir string) (mod verifyModule, ok bool) {
+	for _, m := range v.modules {
+		if m.dir == "." || dir == m.dir || strings.HasPrefix(dir, m.dir+"/") {
+			return m, true
+		}
+	}
+
+	return mod, false
+}
+
+// importPath returns the import path of the package in dir.
+func (v *verifier) importPath(dir string) (string, bool) {
+	m, ok := v.module(dir)
+	if !ok {
+		return "", false
+	}
+
+	rel := strings.TrimPrefix(strings.TrimPrefix(dir, m.dir), "/")
+	if m.dir == "." {
+		rel = strings.TrimPrefix(dir, ".")
+	}
+
+	return strings.TrimSuffix(path.Join(m.path, rel), "/"), true
+}
+
+// parse parses a file, recording any syntax errors.
+func (v *verifier) parse(rel string) *ast.File {
+	src, err := afero.ReadFile(v.afs, filepath.Join(v.projDir, rel))
+	if err != nil {
+		v.problem(rel, 0, "%v", err)
+		return nil
+	}
+
+	f, err := parser.ParseFile(v.fset, rel, src, parser.ParseComments)
+	if err != nil {
+		var list scanner.ErrorList
+		if errors.As(err, &list) {
+			for _, e := range list {
+				v.problem(rel, e.Pos.Line, "%s", e.Msg)
+			}
+			return nil
+		}
+		v.problem(rel, 0, "%v", err)
+		return nil
+	}
+
+	return f
+}
+
+// checkImports makes sure every import in a file can come from somewhere: the standard library, the file's own
+// module, or one of that module's requirements.
+func (v *verifier) checkImports(rel string, f *ast.File) {
+	mod, inModule := v.module(path.Dir(rel))
+
+	for _, spec := range f.Imports {
+		importPath := strings.Trim(spec.Path.Value, "\"`")
+		line := v.fset.Position(spec.Pos()).Line
+
+		switch {
+		case importPath == "C" || !strings.Contains(strings.Split(importPath, "/")[0], "."):
+			// The standard library, which go/types checks.
+		case !inModule:
+			v.problem(rel, line, "imports %s, but isn't in a module", importPath)
+		case importPath == mod.path || strings.HasPrefix(importPath, mod.path+"/"):
+			dir := path.Join(mod.dir, strings.TrimPrefix(strings.TrimPrefix(importPath, mod.path), "/"))
+			if !v.hasGoFiles(dir) {
+				v.problem(rel, line, "imports %s, but the project has no package at %s", importPath, dir)
+			}
+		case !requires(mod, importPath):
+			v.problem(rel, line, "imports %s, which isn't in module %s or any module it requires", importPath, mod.path)
+		}
+	}
+}
+
+// requires reports whether a module requires the module providing importPath.
+func requires(mod verifyModule, importPath string) bool {
+	for _, r := range mod.reqs {
+		if importPath == r || strings.HasPrefix(importPath, r+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v *verifier) hasGoFiles(dir string) bool {
+	entries, err := afero.ReadDir(v.afs, filepath.Join(v.projDir, dir))
+	if err != nil {
+		return false
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".go") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// buildable evaluates a file's //go:build line for the current platform.
+func buildable(f *ast.File) bool {
+	for _, group := range f.Comments {
+		if group.Pos() > f.Package {
+			break
+		}
+
+		for _, c := range group.List {
+			if !constraint.IsGoBuild(c.Text) {
+				continue
+			}
+
+			expr, err := constraint.Parse(c.Text)
+			if err != nil {
+				return false
+			}
+
+			return expr.Eval(func(tag string) bool {
+				return tag == runtime.GOOS || tag == runtime.GOARCH || tag == runtime.Compiler || tag == "unix" && runtime.GOOS != "windows" ||
+					strings.HasPrefix(tag, "go1.")
+			})
+		}
+	}
+
+	return true
+}
+
+// check type checks a package of the project, recording what's wrong with it.
+func (v *verifier) check(importPath string) (*types.Package, error) {
+	pkg := v.pkgs[importPath]
+	switch {
+	case pkg.checked != nil || pkg.err != nil:
+		return pkg.checked, pkg.err
+	case pkg.busy:
+		return nil, fmt.Errorf("import cycle through %s", importPath)
+	}
+	pkg.busy = true
+	defer func() { pkg.busy = false }()
+
+	conf := types.Config{
+		Importer: v,
+		Error: func(err error) {
+			var te types.Error
+			if !errors.As(err, &te) || strings.Contains(te.Msg, "could not import") {
+				return
+			}
+
+			pos := te.Fset.Position(te.Pos)
+			v.problem(pos.Filename, pos.Line, "%s", te.Msg)
+		},
+	}
+
+	// Errors are reported through conf.Error; whatever could be checked is still usable by importers.
+	pkg.checked, _ = conf.Check(importPath, v.fset, pkg.files, nil)
+	if pkg.checked == nil {
+		pkg.err = fmt.Errorf("failed to type check %s", importPath)
+	}
+
+	return pkg.checked, pkg.err
+}
+
+// Import implements types.Importer, loading the project's packages from source and the standard library's through
+// stdImporter.  Anything else is unresolved.
+func (v *verifier) Import(importPath string) (*types.Package, error) {
+	if _, ok := v.pkgs[importPath]; ok {
+		return v.check(importPath)
+	}
+
+	if strings.Contains(strings.Split(importPath, "/")[0], ".") {
+		// go/types treats the package as a stand-in that anything can be used from, but only if it has the name the
+		// importing code uses.
+		return types.NewPackage(importPath, assumedPackageName(importPath)), fmt.Errorf("%w: %s", errUnresolved, importPath)
+	}
+
+	stdImporter.Lock()
+	defer stdImporter.Unlock()
+
+	return stdImporter.Import(importPath)
+}
+
+// assumedPackageName guesses the name of the package at importPath the way goimports does, e.g. github.com/foo/go-bar
+// is bar and gopkg.in/yaml.v3 is yaml.
+func assumedPackageName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+
+	name = strings.TrimPrefix(name, "go-")
+	if i := strings.IndexFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' }); i > 0 {
+		name = name[:i]
+	}
+
+	return name
+}
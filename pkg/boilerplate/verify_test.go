@@ -0,0 +1,124 @@
+/*
+	Copyright <2023> Nik Ogura <nik.ogura@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package boilerplate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const verifyGoMod = "module github.com/example/demo\n\ngo 1.22\n\nrequire github.com/mitchellh/go-homedir v1.1.0\n"
+
+func TestVerifyProject(t *testing.T) {
+	for _, tc := range []struct {
+		Name   string
+		Files  map[string]string
+		Module string
+		Want   []string
+	}{
+		{
+			Name: "Clean",
+			Files: map[string]string{
+				"go.mod":          verifyGoMod,
+				"main.go":         "package main\n\nimport (\n\t\"fmt\"\n\n\t\"github.com/example/demo/pkg/demo\"\n)\n\nfunc main() {\n\tfmt.Println(demo.Home())\n}\n",
+				"pkg/demo/dir.go": "package demo\n\nimport \"github.com/mitchellh/go-homedir\"\n\nfunc Home() string {\n\td, _ := homedir.Dir()\n\treturn d\n}\n",
+				"_tmpl/bad.go":    "not go at all",
+			},
+			Module: "github.com/example/demo",
+		},
+		{
+			Name: "Syntax error",
+			Files: map[string]string{
+				"go.mod":  verifyGoMod,
+				"main.go": "package main\n\nfunc main() {\n\tx := \n}\n",
+			},
+			Want: []string{"main.go:5: expected operand, found '}'"},
+		},
+		{
+			Name: "Type error",
+			Files: map[string]string{
+				"go.mod":  verifyGoMod,
+				"main.go": "package main\n\nfunc main() {\n\tvar n int = \"three\"\n\t_ = n\n\tundefinedFunc()\n}\n",
+			},
+			Want: []string{
+				"main.go:4: cannot use \"three\" (untyped string constant) as int value in variable declaration",
+				"main.go:6: undefined: undefinedFunc",
+			},
+		},
+		{
+			Name: "Bad imports",
+			Files: map[string]string{
+				"go.mod":  verifyGoMod,
+				"main.go": "package main\n\nimport (\n\t_ \"github.com/example/demo/pkg/missing\"\n\t_ \"github.com/example/exampleservice/pkg/demo\"\n)\n\nfunc main() {}\n",
+			},
+			Want: []string{
+				"main.go:4: imports github.com/example/demo/pkg/missing, but the project has no package at pkg/missing",
+				"main.go:5: imports github.com/example/exampleservice/pkg/demo, which isn't in module github.com/example/demo or any module it requires",
+			},
+		},
+		{
+			Name: "Module mismatch",
+			Files: map[string]string{
+				"go.mod":  verifyGoMod,
+				"main.go": "package main\n\nfunc main() {}\n",
+			},
+			Module: "github.com/example/other",
+			Want:   []string{"go.mod:1: module is github.com/example/demo, but the project package is github.com/example/other"},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			afs := afero.NewMemMapFs()
+			for name, data := range tc.Files {
+				require.NoError(t, afero.WriteFile(afs, "/proj/"+name, []byte(data), 0644))
+			}
+
+			err := VerifyProject(afs, "/proj", tc.Module)
+			if len(tc.Want) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var verr *VerifyError
+			require.True(t, errors.As(err, &verr), "expected a verify error, got: %v", err)
+
+			var got []string
+			for _, p := range verr.Problems {
+				got = append(got, p.String())
+			}
+			assert.Equal(t, tc.Want, got)
+		})
+	}
+}
+
+// TestVerifyProject_BuiltinTypes generates every built-in project type and checks the result compiles, as far as
+// it can be without downloading its dependencies.
+func TestVerifyProject_BuiltinTypes(t *testing.T) {
+	vals := goldenAnswers()
+
+	for _, pt := range builtinProjectTypes() {
+		t.Run(pt.Name, func(t *testing.T) {
+			params, err := pt.Params(vals, true)
+			require.NoError(t, err)
+			datamap, err := params.AsMap()
+			require.NoError(t, err)
+
+			afs := afero.NewMemMapFs()
+			w, err := NewTmplWriter(afs, pt.Name, datamap)
+			require.NoError(t, err)
+			require.NoError(t, w.BuildProject("/out"))
+
+			assert.NoError(t, VerifyProject(afs, "/out/"+w.ProjectRoot(), vals["ProjectPackage"]))
+		})
+	}
+}
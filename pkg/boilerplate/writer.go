@@ -11,13 +11,19 @@ package boilerplate
 
 import (
 	"bytes"
-	"embed"
+	"errors"
 	"fmt"
 	"github.com/spf13/afero"
 	"io"
+	"io/fs"
+	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 )
 
 type FilePath struct {
@@ -26,88 +32,385 @@ type FilePath struct {
 	TemplPath string
 	TemplName string
 	IsDir     bool
+
+	// Excluded is set when the path isn't generated, because a file rule excludes it or it resolved to an empty name.
+	Excluded bool
+
+	// Mode is the permissions the file is written with.  Zero means 0644.
+	Mode fs.FileMode
+
+	// Verbatim files are copied without being evaluated as templates.
+	Verbatim bool
 }
 
 type TmplWriter struct {
-	OutFs     afero.Fs
-	TemplFs   embed.FS
-	FilePaths []FilePath
-	ProjDir   string
-	TmplVals  map[string]any
+	OutFs      afero.Fs
+	TemplFs    fs.FS
+	FilePaths  []FilePath
+	ProjDir    string
+	ProjType   string
+	Source     string
+	TmplVals   map[string]any
+	OnConflict ConflictPolicy
+	FileRules  []FileRule
+
+	// Renames moves resolved paths, relative to the project root.  A renamed directory takes its contents with it.
+	Renames map[string]string
+
+	// Now, if set, is the clock behind the now and year template functions, so output can be reproduced exactly.
+	Now func() time.Time
+
+	// Delims replaces the {{ }} delimiters in file contents, though not in paths.  Files can override it with a
+	// front-matter directive.
+	Delims [2]string
+
+	// Secrets are the keys of answers, e.g. to PromptSecret questions, that are never written to the manifest.
+	Secrets []string
+
+	// skip holds the resolved paths BuildProject leaves alone under OnConflictSkip.
+	skip map[string]bool
 }
 
 func NewTmplWriter(outFs afero.Fs, projType string, vals map[string]any) (TmplWriter, error) {
-	fs, dirName, err := GetProjectFs(projType)
-	if err != nil {
-		return TmplWriter{}, fmt.Errorf("fs error: %w", err)
+	pt, ok := LookupProjectType(projType)
+	if !ok {
+		return TmplWriter{}, invalidTypeError(projType)
 	}
 
+	w, err := NewTmplWriterFromFs(outFs, pt.Fs, pt.Root, projType, vals)
+	w.FileRules = append(w.FileRules, pt.FileRules...)
+
+	return w, err
+}
+
+// NewTmplWriterFromFs creates a writer for the template tree found at root in templFs.  The tree has the same shape
+// as the embedded ones, e.g. root/{{.ProjectName}}/...  File rules in the tree's boilerplate.yaml, if any, apply.
+func NewTmplWriterFromFs(outFs afero.Fs, templFs fs.FS, root string, projType string, vals map[string]any) (TmplWriter, error) {
 	w := TmplWriter{
 		OutFs:    outFs,
-		TemplFs:  fs,
-		ProjDir:  dirName,
+		TemplFs:  templFs,
+		ProjDir:  root,
+		ProjType: projType,
 		TmplVals: vals}
 
-	w.FilePaths, err = w.GetFilePaths(dirName)
+	spec, err := LoadTemplateSpec(templFs, root)
+	switch {
+	case err == nil:
+		w.FileRules = spec.Files
+		w.Delims = spec.Delims
+		w.Renames = spec.Rename
+	case !errors.Is(err, ErrNoTemplateSpec):
+		return w, err
+	}
+
+	w.FilePaths, err = w.GetFilePaths(root)
 	if err != nil {
-		return w, fmt.Errorf("failed to walk filepath from root(%s): %w", ".", err)
+		return w, fmt.Errorf("failed to walk filepath from root(%s): %w", root, err)
 	}
 	return w, nil
 }
 
+// BuildProject renders the project and writes it into destDir.  The whole tree is rendered into memory first, and
+// only written out once every template has resolved.  If writing fails part way, everything written so far is
+// rolled back, so an error never leaves a partial project behind.
 func (w TmplWriter) BuildProject(destDir string) error {
-	//fp := w.FilePaths
+	_, err := w.buildProject(destDir)
+	return err
+}
+
+// buildProject is BuildProject, also returning the plan it carried out, worked out from the same rendering.
+func (w TmplWriter) buildProject(destDir string) (plan []PlannedFile, err error) {
+	err = w.ResolveAllPathTemplates()
+	if err != nil {
+		return plan, err
+	}
+
+	var backups []string
+	w.skip, backups, err = w.resolveConflicts(destDir)
+	if err != nil {
+		return plan, err
+	}
+
+	staged, err := w.stage(destDir)
+	if err != nil {
+		return plan, err
+	}
+
+	plan, err = w.planRendered(staged, destDir)
+	if err != nil {
+		return plan, err
+	}
+
+	err = w.commit(staged, destDir, backups)
+	return plan, err
+}
+
+// RenderProject renders the project into an in-memory file system at destDir, leaving OutFs untouched.
+func (w TmplWriter) RenderProject(destDir string) (afero.Fs, error) {
 	err := w.ResolveAllPathTemplates()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	w.fixGoModTemplPaths()
+	return w.stage(destDir)
+}
+
+// stage renders every resolved file, plus the manifest, into a fresh in-memory file system.  Files the conflict policy
+// leaves alone are rendered too, so they can be planned, but commit doesn't copy them, and the manifest doesn't
+// record them.
+func (w TmplWriter) stage(destDir string) (afero.Fs, error) {
+	staged := w
+	staged.OutFs = afero.NewMemMapFs()
 
-	err = w.CreateAllFilePathsAtRoot(destDir)
+	err := staged.CreateAllFilePathsAtRoot(destDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = w.WriteAllDestFileTemplateData(destDir)
+	err = staged.WriteAllDestFileTemplateData(destDir)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	err = staged.WriteManifest(destDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return staged.OutFs, nil
+}
+
+// commit copies a staged tree into OutFs, backing up the given files first, and leaving alone those the conflict
+// policy skips.  On failure it undoes everything it did.
+func (w TmplWriter) commit(staged afero.Fs, destDir string, backups []string) (err error) {
+	var createdDirs, createdFiles []string
+	originals := make(map[string][]byte)
+	origModes := make(map[string]fs.FileMode)
+	movedBackups := make(map[string]string)
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		for _, f := range createdFiles {
+			_ = w.OutFs.Remove(f)
+		}
+		for path, data := range originals {
+			_ = afero.WriteFile(w.OutFs, path, data, origModes[path])
+			_ = w.OutFs.Chmod(path, origModes[path])
+		}
+		for path, backup := range movedBackups {
+			_ = w.OutFs.Rename(backup, path)
+		}
+		for i := len(createdDirs) - 1; i >= 0; i-- {
+			_ = w.OutFs.Remove(createdDirs[i])
+		}
+	}()
+
+	for _, b := range backups {
+		path := filepath.Join(destDir, b)
+		backup, backupErr := w.backupFile(path)
+		if backupErr != nil {
+			err = backupErr
+			return err
+		}
+		movedBackups[path] = backup
+	}
+
+	err = afero.Walk(staged, destDir, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		if !info.IsDir() && w.skipped(destDir, path) {
+			return nil
+		}
+
+		existing, statErr := w.OutFs.Stat(path)
+		if statErr != nil && !os.IsNotExist(statErr) {
+			return fmt.Errorf("failed to stat %s: %w", path, statErr)
+		}
+
+		if info.IsDir() {
+			if statErr == nil {
+				if !existing.IsDir() {
+					return fmt.Errorf("cannot create directory %s: a file is in the way", path)
+				}
+				return nil
+			}
+
+			mkErr := w.OutFs.MkdirAll(path, 0755)
+			if mkErr != nil {
+				return fmt.Errorf("directory creation failed: %w", mkErr)
+			}
+			createdDirs = append(createdDirs, path)
+			return nil
+		}
+
+		if statErr == nil {
+			orig, readErr := afero.ReadFile(w.OutFs, path)
+			if readErr != nil {
+				return fmt.Errorf("failed to read %s before overwriting: %w", path, readErr)
+			}
+			originals[path] = orig
+			origModes[path] = existing.Mode().Perm()
+		} else {
+			createdFiles = append(createdFiles, path)
+		}
+
+		data, readErr := afero.ReadFile(staged, path)
+		if readErr != nil {
+			return fmt.Errorf("failed to read staged file %s: %w", path, readErr)
+		}
+
+		writeErr := afero.WriteFile(w.OutFs, path, data, info.Mode().Perm())
+		if writeErr != nil {
+			return fmt.Errorf("cannot write file(%s) bytes: %w", path, writeErr)
+		}
+
+		// WriteFile only applies the mode to new files.
+		chmodErr := w.OutFs.Chmod(path, info.Mode().Perm())
+		if chmodErr != nil {
+			return fmt.Errorf("cannot set mode of file(%s): %w", path, chmodErr)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// ProjectRoot returns the top level directory of the resolved project tree, or "" if the tree has several.
+func (w TmplWriter) ProjectRoot() string {
+	root := ""
+	for _, fp := range w.FilePaths {
+		if fp.Excluded {
+			continue
+		}
+
+		top := strings.SplitN(fp.TemplPath, "/", 2)[0]
+		if root != "" && top != root {
+			return ""
+		}
+		root = top
+	}
+
+	return root
+}
+
+// skipped reports whether the conflict policy leaves path, below destDir, alone.  A manifest left alone keeps its base.
+func (w TmplWriter) skipped(destDir, path string) bool {
+	rel, err := filepath.Rel(destDir, path)
+	if err != nil || len(w.skip) == 0 {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	root := w.ProjectRoot()
+	if w.skip[filepath.Join(root, ManifestFileName)] && strings.HasPrefix(rel, filepath.ToSlash(filepath.Join(root, BaseDir))+"/") {
+		return true
+	}
+
+	return w.skip[rel]
+}
+
+// WriteManifest records the project type, template version, values, and a hash of every written file in the project
+// root, with a copy of each below BaseDir, so the project can later be updated to newer templates.  Secrets are left
+// out of the values.
+func (w TmplWriter) WriteManifest(destDir string) error {
+	root := w.ProjectRoot()
+
+	m := Manifest{
+		ProjectType:    w.ProjType,
+		TemplateSource: w.Source,
+		Version:        VERSION,
+		Values:         withoutKeys(w.TmplVals, w.Secrets),
+		Secrets:        w.Secrets,
+		Files:          make(map[string]string),
+	}
+
+	base := make(map[string][]byte)
+	for _, fp := range w.FilePaths {
+		if fp.IsDir || fp.Excluded || w.skip[fp.TemplPath] {
+			continue
+		}
+
+		data, err := afero.ReadFile(w.OutFs, filepath.Join(destDir, fp.TemplPath))
+		if err != nil {
+			return fmt.Errorf("failed to read back file(%s) for manifest: %w", fp.TemplPath, err)
+		}
+
+		rel := fp.TemplPath
+		if root != "" {
+			rel = strings.TrimPrefix(rel, root+"/")
+		}
+		m.Files[rel] = HashContent(data)
+		base[rel] = data
+	}
+
+	err := m.Write(w.OutFs, filepath.Join(destDir, root))
+	if err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	err = WriteBase(w.OutFs, filepath.Join(destDir, root), base)
+	if err != nil {
+		return fmt.Errorf("failed to write manifest base: %w", err)
 	}
 
 	return nil
 }
 
+// ResolveAllPathTemplates works out where every template file is generated: it renders each path, renames the result
+// (see applyRenames), then applies the file rules.
 func (w TmplWriter) ResolveAllPathTemplates() error {
 	for i := range w.FilePaths {
 		fp := w.FilePaths[i]
-		buf, err := w.ResolveTemplateVars(fp.Path)
+		rel := fp.Path
+		if w.ProjDir != "." {
+			rel = strings.TrimPrefix(rel, w.ProjDir+"/")
+		}
+
+		buf, err := w.resolveNamedTemplate(fp.Path, rel)
 		if err != nil {
 			return fmt.Errorf("path resolution failure: path=%s, err=%w", fp.Path, err)
-		} else {
-			path := strings.Replace(buf.String(), w.ProjDir, "", 1)
-			if path[0] == '/' {
-				path = path[1:]
-			}
-			w.FilePaths[i].TemplPath = path
 		}
+		w.FilePaths[i].TemplPath = buf.String()
 
 		buf, err = w.ResolveTemplateVars(fp.Name)
 		if err != nil {
 			return fmt.Errorf("name resolution failure: path=%s, err=%w", fp.Name, err)
-		} else {
-			name := strings.Replace(buf.String(), w.ProjDir, "", 1)
-			if name[0] == '/' {
-				name = name[1:]
-			}
-			w.FilePaths[i].TemplName = name
 		}
+		w.FilePaths[i].TemplName = buf.String()
 	}
 
-	return nil
+	w.applyRenames()
+
+	return w.applyFileRules()
+}
+
+// TemplateError reports a template that failed to parse or execute, and where.
+type TemplateError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *TemplateError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %v", e.File, e.Err)
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
 }
 
 func (w TmplWriter) ResolveTemplateVars(str string) (*bytes.Buffer, error) {
-	tmpl, err := template.New("tmplWriter").Parse(str)
+	tmpl, err := w.newTemplate("tmplWriter").Parse(str)
 	if err != nil {
 		return nil, fmt.Errorf("path parsing error: %w", err)
 	}
@@ -121,8 +424,57 @@ func (w TmplWriter) ResolveTemplateVars(str string) (*bytes.Buffer, error) {
 	return buf, nil
 }
 
+// resolveNamedTemplate executes a template read from file, reporting failures as a *TemplateError.
+func (w TmplWriter) resolveNamedTemplate(file, str string) (*bytes.Buffer, error) {
+	return w.resolveDelimitedTemplate(file, str, [2]string{})
+}
+
+// resolveDelimitedTemplate is resolveNamedTemplate with alternate delimiters.  Empty delimiters mean {{ }}.
+func (w TmplWriter) resolveDelimitedTemplate(file, str string, delims [2]string) (*bytes.Buffer, error) {
+	tmpl, err := w.newTemplate(file).Delims(delims[0], delims[1]).Parse(str)
+	if err != nil {
+		return nil, newTemplateError(file, err)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = tmpl.Execute(buf, w.TmplVals)
+	if err != nil {
+		return nil, newTemplateError(file, err)
+	}
+
+	return buf, nil
+}
+
+// newTemplate creates a template with the template functions, using the writer's clock if it has one.  Templates
+// referring to a value the project type doesn't provide fail, rather than rendering "<no value>".
+func (w TmplWriter) newTemplate(name string) *template.Template {
+	tmpl := newTemplate(name).Option("missingkey=error")
+	if w.Now != nil {
+		tmpl = tmpl.Funcs(clockFuncs(w.Now))
+	}
+
+	return tmpl
+}
+
+// newTemplateError extracts the line number text/template embeds in its errors, which look like
+// "template: <name>:<line>:..." for both parse and exec failures.
+func newTemplateError(file string, err error) *TemplateError {
+	te := &TemplateError{File: file, Err: err}
+
+	re := regexp.MustCompile(`^template: ` + regexp.QuoteMeta(file) + `:(\d+):`)
+	if m := re.FindStringSubmatch(err.Error()); m != nil {
+		te.Line, _ = strconv.Atoi(m[1])
+	}
+
+	return te
+}
+
 func (w TmplWriter) CreateAllFilePathsAtRoot(root string) error {
 	for _, fp := range w.FilePaths {
+		if fp.Excluded {
+			continue
+		}
+
 		err := w.CreatePath(root, fp.TemplPath, fp.IsDir)
 		if err != nil {
 			return fmt.Errorf("failed to create file(%s): err(%w)", fp.TemplPath, err)
@@ -151,7 +503,7 @@ func (w TmplWriter) CreatePath(root, file string, isDir bool) error {
 
 func (w TmplWriter) WriteAllDestFileTemplateData(destDir string) error {
 	for _, fp := range w.FilePaths {
-		if fp.IsDir {
+		if fp.IsDir || fp.Excluded {
 			continue
 		}
 
@@ -164,19 +516,38 @@ func (w TmplWriter) WriteAllDestFileTemplateData(destDir string) error {
 	return nil
 }
 
-func (w TmplWriter) WriteFileTemplateData(fp FilePath, destDir string) error {
+func (w TmplWriter) WriteFileTemplateData(fp FilePath, destDir string) (err error) {
 	path := fmt.Sprintf("%s/%s", destDir, fp.TemplPath)
-	file, err := w.OutFs.Create(path)
+
+	mode := fp.Mode
+	if mode == 0 {
+		mode = 0644
+	}
+
+	verbatim, err := w.isVerbatim(fp)
 	if err != nil {
-		return fmt.Errorf("failed to create file(%s): %w", path, err)
+		return err
 	}
-	defer func() {
-		closeErr := file.Close()
-		if closeErr != nil { //nolint:staticcheck // close error handling not needed
-			// Log or handle close error if needed
-		}
-	}()
 
+	if verbatim {
+		err = w.copyFile(fp, path)
+	} else {
+		err = w.writeTemplateFile(fp, path)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = w.OutFs.Chmod(filepath.Clean(path), mode)
+	if err != nil {
+		return fmt.Errorf("cannot set mode of file(%s): %w", path, err)
+	}
+
+	return nil
+}
+
+// writeTemplateFile renders a template file into path.
+func (w TmplWriter) writeTemplateFile(fp FilePath, path string) error {
 	buf, err := w.ResolveFileTemplateData(fp)
 	if err != nil {
 		return fmt.Errorf("failed to exec template: %w", err)
@@ -187,6 +558,17 @@ func (w TmplWriter) WriteFileTemplateData(fp FilePath, destDir string) error {
 		return fmt.Errorf("failed to remove build exclusions from file(%s): %w", fp.TemplName, err)
 	}
 
+	file, err := w.OutFs.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file(%s): %w", path, err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil { //nolint:staticcheck // close error handling not needed
+			// Log or handle close error if needed
+		}
+	}()
+
 	n, err := file.Write(buf.Bytes())
 	if err != nil {
 		return fmt.Errorf("cannot write file(%s) bytes: %w", path, err)
@@ -197,19 +579,84 @@ func (w TmplWriter) WriteFileTemplateData(fp FilePath, destDir string) error {
 	return nil
 }
 
+// isVerbatim reports whether a file is copied as is, either because a file rule says so or because it is binary.
+func (w TmplWriter) isVerbatim(fp FilePath) (verbatim bool, err error) {
+	if fp.Verbatim {
+		return true, err
+	}
+
+	src, err := w.TemplFs.Open(fp.Path)
+	if err != nil {
+		return false, fmt.Errorf("cannot read file data(%s): err(%w)", fp.Path, err)
+	}
+	defer src.Close()
+
+	head := make([]byte, binarySniffLen)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return false, fmt.Errorf("cannot read file data(%s): err(%w)", fp.Path, err)
+	}
+
+	return isBinary(head[:n]), nil
+}
+
+// copyFile streams a template file into path untouched.
+func (w TmplWriter) copyFile(fp FilePath, path string) (err error) {
+	src, err := w.TemplFs.Open(fp.Path)
+	if err != nil {
+		return fmt.Errorf("cannot read file data(%s): err(%w)", fp.Path, err)
+	}
+	defer src.Close()
+
+	file, err := w.OutFs.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file(%s): %w", path, err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close file(%s): %w", path, closeErr)
+		}
+	}()
+
+	_, err = io.Copy(file, src)
+	if err != nil {
+		return fmt.Errorf("cannot write file(%s) bytes: %w", path, err)
+	}
+
+	return err
+}
+
+// ResolveFileTemplateData renders a template file.  A front-matter directive on the first line can switch the file to
+// alternate delimiters, or mark it verbatim so it is copied as is.  Otherwise the writer's Delims apply.
 func (w TmplWriter) ResolveFileTemplateData(fp FilePath) (*bytes.Buffer, error) {
 	// Read the original file data not the parsed template path
-	file, err := w.TemplFs.Open(fp.Path)
+	data, err := fs.ReadFile(w.TemplFs, fp.Path)
 	if err != nil {
-		return nil, fmt.Errorf("cannot open file(%s): err(%w)", fp.Path, err)
+		return nil, fmt.Errorf("cannot read file data(%s): err(%w)", fp.Path, err)
 	}
-	data, err := io.ReadAll(file)
+
+	dirs, body, found, err := parseFrontMatter(string(data))
 	if err != nil {
-		return nil, fmt.Errorf("cannot read file data(%s): err(%w)", fp.Path, err)
+		return nil, fmt.Errorf("cannot execute template with file(%s): %w", fp.Path, &TemplateError{File: fp.Path, Line: 1, Err: err})
+	}
+
+	if dirs.Verbatim {
+		return bytes.NewBufferString(body), nil
+	}
+
+	delims := w.Delims
+	if dirs.Delims[0] != "" {
+		delims = dirs.Delims
 	}
 
-	buf, err := w.ResolveTemplateVars(string(data))
+	buf, err := w.resolveDelimitedTemplate(fp.Path, body, delims)
 	if err != nil {
+		// Report lines as they are in the template file, directive included.
+		var te *TemplateError
+		if found && errors.As(err, &te) && te.Line > 0 {
+			te.Line++
+		}
 		return nil, fmt.Errorf("cannot execute template with file(%s): %w", fp.Path, err)
 	}
 
@@ -219,13 +666,19 @@ func (w TmplWriter) ResolveFileTemplateData(fp FilePath) (*bytes.Buffer, error)
 func (w TmplWriter) GetFilePaths(root string) ([]FilePath, error) {
 	var fp []FilePath
 
-	entries, err := w.TemplFs.ReadDir(root)
+	entries, err := fs.ReadDir(w.TemplFs, root)
 	if err != nil {
-		return fp, fmt.Errorf("failed to read embedded files at dir(%s): %w", root, err)
+		return fp, fmt.Errorf("failed to read template files at dir(%s): %w", root, err)
 	}
 
 	for _, e := range entries {
-		cpath := fmt.Sprintf("%s/%s", root, e.Name())
+		// The template spec and the tree's test data describe the tree; they aren't part of it.  Nor is the .git of a
+		// --template-dir that is a git working copy.
+		if root == w.ProjDir && (e.Name() == TemplateSpecFileName || e.Name() == templateTestDataDir || e.Name() == ".git") {
+			continue
+		}
+
+		cpath := path.Join(root, e.Name())
 		if e.IsDir() {
 			// Add directory entry
 			fp = append(fp, FilePath{
@@ -240,10 +693,16 @@ func (w TmplWriter) GetFilePaths(root string) ([]FilePath, error) {
 			}
 			fp = append(fp, children...)
 		} else {
+			info, infoErr := e.Info()
+			if infoErr != nil {
+				return nil, fmt.Errorf("failed to stat template file(%s): %w", cpath, infoErr)
+			}
+
 			fp = append(fp, FilePath{
 				Path:  cpath,
 				Name:  e.Name(),
 				IsDir: false,
+				Mode:  sourceMode(info.Mode()),
 			})
 		}
 	}
@@ -251,22 +710,22 @@ func (w TmplWriter) GetFilePaths(root string) ([]FilePath, error) {
 	return fp, nil
 }
 
-func (w TmplWriter) fixGoModTemplPaths() {
-	for i := range w.FilePaths {
-		fp := w.FilePaths[i]
-		if fp.TemplName == "go.mod_" || fp.TemplName == "go.sum_" {
-			fp.TemplPath = fp.TemplPath[:len(fp.TemplPath)-1]
-			fp.TemplName = fp.TemplName[:len(fp.TemplName)-1]
-		}
-		w.FilePaths[i] = fp
+// sourceMode maps a template file's mode to the mode it is generated with: 0755 if it is executable by anyone, 0644
+// otherwise.  Template trees are often read only, e.g. embedded ones, so their exact permissions aren't kept.
+func sourceMode(mode fs.FileMode) fs.FileMode {
+	if mode&0111 != 0 {
+		return 0755
 	}
+
+	return 0644
 }
 
-func (w TmplWriter) removeBuildExclusions(buf *bytes.Buffer) (*bytes.Buffer, error) {
-	replBuf, err := w.ResolveTemplateVars("// +build exclude {{.ProjectName}}\n")
-	if err != nil {
-		return nil, fmt.Errorf("removing build exclusions cannot execute template: %w", err)
-	}
+// buildExclusionRe matches a build constraint that keeps a Go template out of this module's build, e.g.
+// "//go:build exclude" or the older "// +build exclude myproject".
+var buildExclusionRe = regexp.MustCompile(`(?m)^//\s*(?:go:build|\+build)\s+exclude\b.*\n`) //nolint:gochecknoglobals // compiled once
 
-	return bytes.NewBufferString(strings.ReplaceAll(buf.String(), replBuf.String(), "")), nil
+// removeBuildExclusions drops the build constraints that keep Go templates out of this module's build, so the
+// generated files build.
+func (w TmplWriter) removeBuildExclusions(buf *bytes.Buffer) (*bytes.Buffer, error) {
+	return bytes.NewBufferString(buildExclusionRe.ReplaceAllString(buf.String(), "")), nil
 }
@@ -10,9 +10,14 @@ THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLI
 package boilerplate
 
 import (
+	"errors"
 	"fmt"
 	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func MapOnly(hash map[string]interface{}, err error) map[string]interface{} {
@@ -86,3 +91,572 @@ func TestNewTmplWriter_BuildProject(t *testing.T) { //nolint:gocognit // test co
 		})
 	}
 }
+
+func testCobraParams() map[string]interface{} {
+	return MapOnly((&CobraCliToolParams{
+		ProjectName:      "test-proj",
+		ProjectPackage:   "github.com/test/test-proj",
+		ProjectShortDesc: "proj short",
+		ProjectLongDesc:  "proj long",
+		MaintainerName:   "test",
+		MaintainerEmail:  "test@example.com",
+		DbtRepo:          "https://dbt",
+		ProjectVersion:   "0.1.0",
+		GolangVersion:    "1.22",
+	}).AsMap())
+}
+
+func TestTmplWriter_PlanAndDiff(t *testing.T) {
+	afs := afero.NewMemMapFs()
+	w, err := NewTmplWriter(afs, CobraProjectType, testCobraParams())
+	if err != nil {
+		t.Fatalf("failed to build template writer: %v", err)
+	}
+
+	err = afero.WriteFile(afs, "/out/test-proj/README.md", []byte("# hand written\n"), 0644)
+	if err != nil {
+		t.Fatalf("failed to seed destination: %v", err)
+	}
+
+	plan, err := w.Plan("/out")
+	if err != nil {
+		t.Fatalf("failed to plan project: %v", err)
+	}
+
+	actions := make(map[string]string)
+	for _, pf := range plan {
+		actions[pf.TemplPath] = pf.Action
+		if pf.Size == 0 {
+			t.Errorf("unexpected empty file in plan: %s", pf.TemplPath)
+		}
+	}
+
+	// By default an existing file makes the build fail.
+	if actions["test-proj/README.md"] != ActionConflict {
+		t.Errorf("expected README.md to conflict, got %q", actions["test-proj/README.md"])
+	}
+	if actions["test-proj/go.mod"] != ActionCreate {
+		t.Errorf("expected go.mod to be created, got %q", actions["test-proj/go.mod"])
+	}
+	if _, ok := actions["test-proj/"+BaseDir+"/go.mod"]; ok {
+		t.Errorf("expected the manifest base to be left out of the plan")
+	}
+
+	for policy, want := range map[ConflictPolicy]string{
+		OnConflictFail:      ActionConflict,
+		OnConflictSkip:      ActionSkip,
+		OnConflictOverwrite: ActionOverwrite,
+		OnConflictBackup:    ActionBackup,
+	} {
+		pw := w
+		pw.OnConflict = policy
+		plan, err = pw.Plan("/out")
+		if err != nil {
+			t.Fatalf("failed to plan project with policy %s: %v", policy, err)
+		}
+
+		for _, pf := range plan {
+			if pf.TemplPath == "test-proj/README.md" && pf.Action != want {
+				t.Errorf("expected README.md to %s with policy %s, got %q", want, policy, pf.Action)
+			}
+		}
+	}
+
+	diff, err := w.Diff("/out")
+	if err != nil {
+		t.Fatalf("failed to diff project: %v", err)
+	}
+
+	if !strings.Contains(diff, "--- a/test-proj/README.md\n+++ b/test-proj/README.md\n") {
+		t.Errorf("diff missing README.md changes:\n%s", diff)
+	}
+	if !strings.Contains(diff, "--- /dev/null\n+++ b/test-proj/go.mod\n") {
+		t.Errorf("diff missing new go.mod:\n%s", diff)
+	}
+
+	// Neither mode writes anything.
+	exists, err := afero.Exists(afs, "/out/test-proj/go.mod")
+	if err != nil || exists {
+		t.Errorf("preview wrote to the destination: exists(%v) err(%v)", exists, err)
+	}
+}
+
+func TestTmplWriter_OnConflict(t *testing.T) {
+	for _, tc := range []struct {
+		Name       string
+		Policy     ConflictPolicy
+		ExpErr     bool
+		ExpReadme  string
+		ExpBackup  bool
+		ExpWritten bool
+	}{
+		{
+			Name:   "Default fails",
+			Policy: "",
+			ExpErr: true,
+		},
+		{
+			Name:   "Fail",
+			Policy: OnConflictFail,
+			ExpErr: true,
+		},
+		{
+			Name:       "Skip",
+			Policy:     OnConflictSkip,
+			ExpReadme:  "# hand written\n",
+			ExpWritten: true,
+		},
+		{
+			Name:       "Overwrite",
+			Policy:     OnConflictOverwrite,
+			ExpWritten: true,
+		},
+		{
+			Name:       "Backup",
+			Policy:     OnConflictBackup,
+			ExpBackup:  true,
+			ExpWritten: true,
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			afs := afero.NewMemMapFs()
+			w, err := NewTmplWriter(afs, CobraProjectType, testCobraParams())
+			if err != nil {
+				t.Fatalf("failed to build template writer: %v", err)
+			}
+			w.OnConflict = tc.Policy
+
+			for _, f := range []string{"README.md", "LICENSE"} {
+				err = afero.WriteFile(afs, "/out/test-proj/"+f, []byte("# hand written\n"), 0644)
+				if err != nil {
+					t.Fatalf("failed to seed destination: %v", err)
+				}
+			}
+
+			err = w.BuildProject("/out")
+			if tc.ExpErr {
+				var collisions *CollisionError
+				if !errors.As(err, &collisions) {
+					t.Fatalf("expected a collision error, got: %v", err)
+				}
+				if len(collisions.Paths) != 2 {
+					t.Errorf("expected both collisions to be listed, got: %v", collisions.Paths)
+				}
+
+				exists, _ := afero.Exists(afs, "/out/test-proj/go.mod")
+				if exists {
+					t.Errorf("files were written despite collisions")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("failed to build project: %v", err)
+			}
+
+			readme, _ := afero.ReadFile(afs, "/out/test-proj/README.md")
+			if tc.ExpReadme != "" && string(readme) != tc.ExpReadme {
+				t.Errorf("expected README.md to be left alone, got: %s", readme)
+			}
+			if tc.ExpReadme == "" && string(readme) == "# hand written\n" {
+				t.Errorf("expected README.md to be replaced")
+			}
+
+			backup, _ := afero.ReadFile(afs, "/out/test-proj/README.md.bak")
+			if tc.ExpBackup != (string(backup) == "# hand written\n") {
+				t.Errorf("unexpected backup state: %q", backup)
+			}
+
+			exists, _ := afero.Exists(afs, "/out/test-proj/go.mod")
+			if exists != tc.ExpWritten {
+				t.Errorf("unexpected go.mod state: exists(%v)", exists)
+			}
+		})
+	}
+}
+
+func TestTmplWriter_TemplateErrorLocation(t *testing.T) {
+	w := TmplWriter{TmplVals: map[string]any{"Foo": "bar"}}
+
+	_, err := w.resolveNamedTemplate("some/file.go", "line one\nline two {{.Foo.Bar}}\n")
+	var te *TemplateError
+	if !errors.As(err, &te) {
+		t.Fatalf("expected a template error, got: %v", err)
+	}
+	if te.File != "some/file.go" || te.Line != 2 {
+		t.Errorf("unexpected error location: file(%s) line(%d)", te.File, te.Line)
+	}
+
+	_, err = w.resolveNamedTemplate("some/file.go", "line one\n\n\n{{if}}\n")
+	if !errors.As(err, &te) {
+		t.Fatalf("expected a template error, got: %v", err)
+	}
+	if te.Line != 4 {
+		t.Errorf("unexpected parse error line: %d", te.Line)
+	}
+
+	// A misspelt value fails, naming it, rather than rendering <no value>.
+	_, err = w.resolveNamedTemplate("some/file.go", "line one\n{{.Fo}}\n")
+	if !errors.As(err, &te) {
+		t.Fatalf("expected a template error, got: %v", err)
+	}
+	if te.Line != 2 || !strings.Contains(err.Error(), `"Fo"`) {
+		t.Errorf("unexpected missing value error: %v", err)
+	}
+}
+
+func TestTmplWriter_BuildProjectLeavesNothingOnRenderFailure(t *testing.T) {
+	afs := afero.NewMemMapFs()
+	w, err := NewTmplWriter(afs, CobraProjectType, testCobraParams())
+	if err != nil {
+		t.Fatalf("failed to build template writer: %v", err)
+	}
+
+	// A template that can't be read fails after most of the tree has rendered.
+	w.FilePaths = append(w.FilePaths, FilePath{
+		Path: w.ProjDir + "/{{.ProjectName}}/zz-missing.go",
+		Name: "zz-missing.go",
+	})
+
+	err = w.BuildProject("/out")
+	if err == nil {
+		t.Fatalf("expected build to fail")
+	}
+
+	exists, _ := afero.Exists(afs, "/out")
+	if exists {
+		t.Errorf("failed build left files behind")
+	}
+}
+
+// failingFs fails any write to a file with the given base name.
+type failingFs struct {
+	afero.Fs
+	failOn string
+}
+
+func (f failingFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if filepath.Base(name) == f.failOn && flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+		return nil, errors.New("injected write failure")
+	}
+
+	return f.Fs.OpenFile(name, flag, perm)
+}
+
+func TestTmplWriter_BuildProjectRollsBackOnWriteFailure(t *testing.T) {
+	afs := afero.NewMemMapFs()
+	err := afero.WriteFile(afs, "/out/test-proj/README.md", []byte("# hand written\n"), 0644)
+	if err != nil {
+		t.Fatalf("failed to seed destination: %v", err)
+	}
+
+	w, err := NewTmplWriter(failingFs{Fs: afs, failOn: "main.go"}, CobraProjectType, testCobraParams())
+	if err != nil {
+		t.Fatalf("failed to build template writer: %v", err)
+	}
+	w.OnConflict = OnConflictOverwrite
+
+	err = w.BuildProject("/out")
+	if err == nil {
+		t.Fatalf("expected build to fail")
+	}
+
+	files, err := readTree(afs, "/out")
+	if err != nil {
+		t.Fatalf("failed to read destination: %v", err)
+	}
+	if len(files) != 1 || string(files["test-proj/README.md"]) != "# hand written\n" {
+		t.Errorf("destination not restored after failed write: %v", sortedKeys(files))
+	}
+
+	exists, _ := afero.Exists(afs, "/out/test-proj/cmd")
+	if exists {
+		t.Errorf("created directories not removed after failed write")
+	}
+}
+
+func TestTmplWriter_ConditionalFiles(t *testing.T) {
+	templFs := fstest.MapFS{
+		TemplateSpecFileName: {Data: []byte(`
+name: conditional
+prompts:
+  - key: ProjectName
+    message: name
+files:
+  - pattern: pkg/auth
+    when: .EnableAuth
+  - pattern: "*.xml"
+    when: eq .Docs "all"
+`)},
+		"{{.ProjectName}}/main.go":                        {Data: []byte("package main\n")},
+		"{{.ProjectName}}/prompt.xml":                     {Data: []byte("<Prompt/>\n")},
+		"{{.ProjectName}}/pkg/auth/oidc.go":               {Data: []byte("package auth\n")},
+		"{{.ProjectName}}/{{if .Docs}}docs{{end}}/README": {Data: []byte("docs\n")},
+	}
+
+	for _, tc := range []struct {
+		Name    string
+		Vals    map[string]any
+		Want    []string
+		NotWant []string
+	}{
+		{
+			Name:    "Everything",
+			Vals:    map[string]any{"ProjectName": "cond", "EnableAuth": "true", "Docs": "all"},
+			Want:    []string{"cond/main.go", "cond/prompt.xml", "cond/pkg/auth/oidc.go", "cond/docs/README"},
+			NotWant: []string{},
+		},
+		{
+			Name:    "Excluded",
+			Vals:    map[string]any{"ProjectName": "cond", "EnableAuth": "false", "Docs": ""},
+			Want:    []string{"cond/main.go"},
+			NotWant: []string{"cond/prompt.xml", "cond/pkg/auth", "cond/docs", "cond/README"},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			afs := afero.NewMemMapFs()
+			w, err := NewTmplWriterFromFs(afs, templFs, ".", "conditional", tc.Vals)
+			if err != nil {
+				t.Fatalf("failed to build template writer: %v", err)
+			}
+
+			err = w.BuildProject("/out")
+			if err != nil {
+				t.Fatalf("failed to build project: %v", err)
+			}
+
+			for _, f := range tc.Want {
+				if _, statErr := afs.Stat(filepath.Join("/out", f)); statErr != nil {
+					t.Errorf("expected %s to be generated: %v", f, statErr)
+				}
+			}
+
+			for _, f := range tc.NotWant {
+				if _, statErr := afs.Stat(filepath.Join("/out", f)); statErr == nil {
+					t.Errorf("expected %s to be excluded", f)
+				}
+			}
+
+			m, err := LoadManifest(afs, "/out/cond")
+			if err != nil {
+				t.Fatalf("failed to load manifest: %v", err)
+			}
+			if len(m.Files) != len(tc.Want) {
+				t.Errorf("manifest records %d files, expected %d: %v", len(m.Files), len(tc.Want), m.Files)
+			}
+		})
+	}
+}
+
+func TestTmplWriter_FrontMatter(t *testing.T) {
+	w := TmplWriter{
+		TemplFs: fstest.MapFS{
+			"delims.yaml":   {Data: []byte("# boilerplate: delims=[[ ]]\nname: [[.ProjectName]]\ntag: ${{ steps.semver.outputs.version_tag }}\n")},
+			"verbatim.tmpl": {Data: []byte("<!-- boilerplate: verbatim -->\n{{.Repository}}/{{.Name}}\n")},
+			"plain.txt":     {Data: []byte("# not a directive\n{{.ProjectName}}\n")},
+			"broken.yaml":   {Data: []byte("# boilerplate: delims=[[ ]]\nok: [[.ProjectName]]\nbad: [[.ProjectName.Foo]]\n")},
+			"unknown.txt":   {Data: []byte("// boilerplate: sideways\n")},
+		},
+		TmplVals: map[string]any{"ProjectName": "fm"},
+	}
+
+	for _, tc := range []struct {
+		File string
+		Want string
+	}{
+		{File: "delims.yaml", Want: "name: fm\ntag: ${{ steps.semver.outputs.version_tag }}\n"},
+		{File: "verbatim.tmpl", Want: "{{.Repository}}/{{.Name}}\n"},
+		{File: "plain.txt", Want: "# not a directive\nfm\n"},
+	} {
+		buf, err := w.ResolveFileTemplateData(FilePath{Path: tc.File})
+		if err != nil {
+			t.Errorf("failed to render %s: %v", tc.File, err)
+			continue
+		}
+		if buf.String() != tc.Want {
+			t.Errorf("unexpected output for %s: %q", tc.File, buf.String())
+		}
+	}
+
+	_, err := w.ResolveFileTemplateData(FilePath{Path: "broken.yaml"})
+	var te *TemplateError
+	if !errors.As(err, &te) {
+		t.Fatalf("expected a template error, got: %v", err)
+	}
+	if te.Line != 3 {
+		t.Errorf("expected the error on line 3 of the template file, got %d", te.Line)
+	}
+
+	_, err = w.ResolveFileTemplateData(FilePath{Path: "unknown.txt"})
+	if err == nil || !strings.Contains(err.Error(), "unknown boilerplate directive") {
+		t.Errorf("expected an unknown directive error, got: %v", err)
+	}
+
+	// A template-wide setting from the writer applies to files without a directive of their own.
+	w.Delims = [2]string{"<%", "%>"}
+	w.TemplFs = fstest.MapFS{"spec.txt": {Data: []byte("<%.ProjectName%> {{ kept }}\n")}}
+	buf, err := w.ResolveFileTemplateData(FilePath{Path: "spec.txt"})
+	if err != nil {
+		t.Fatalf("failed to render with template-wide delims: %v", err)
+	}
+	if buf.String() != "fm {{ kept }}\n" {
+		t.Errorf("unexpected output with template-wide delims: %q", buf.String())
+	}
+}
+
+func TestTmplWriter_ModesAndBinaryFiles(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR{{.ProjectName}}")
+	templFs := fstest.MapFS{
+		TemplateSpecFileName: {Data: []byte(`
+name: assets
+prompts:
+  - key: ProjectName
+    message: name
+files:
+  - pattern: scripts
+    mode: "0750"
+  - pattern: static/*.html
+    verbatim: true
+`)},
+		"{{.ProjectName}}/main.go":           {Data: []byte("package {{.ProjectName}}\n")},
+		"{{.ProjectName}}/run.sh":            {Data: []byte("#!/bin/sh\n"), Mode: 0555},
+		"{{.ProjectName}}/scripts/build":     {Data: []byte("#!/bin/sh\n")},
+		"{{.ProjectName}}/static/logo.png":   {Data: png},
+		"{{.ProjectName}}/static/index.html": {Data: []byte("<div>{{.ProjectName}}</div>\n")},
+	}
+
+	afs := afero.NewMemMapFs()
+	w, err := NewTmplWriterFromFs(afs, templFs, ".", "assets", map[string]any{"ProjectName": "assets"})
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+
+	err = w.BuildProject("/out")
+	if err != nil {
+		t.Fatalf("failed to build project: %v", err)
+	}
+
+	for _, tc := range []struct {
+		File string
+		Mode os.FileMode
+		Data string
+	}{
+		{File: "main.go", Mode: 0644, Data: "package assets\n"},
+		{File: "run.sh", Mode: 0755, Data: "#!/bin/sh\n"},
+		{File: "scripts/build", Mode: 0750, Data: "#!/bin/sh\n"},
+		{File: "static/logo.png", Mode: 0644, Data: string(png)},
+		{File: "static/index.html", Mode: 0644, Data: "<div>{{.ProjectName}}</div>\n"},
+	} {
+		path := filepath.Join("/out/assets", tc.File)
+		info, statErr := afs.Stat(path)
+		if statErr != nil {
+			t.Errorf("expected %s to be generated: %v", tc.File, statErr)
+			continue
+		}
+		if info.Mode().Perm() != tc.Mode {
+			t.Errorf("expected %s to have mode %o, got %o", tc.File, tc.Mode, info.Mode().Perm())
+		}
+
+		data, _ := afero.ReadFile(afs, path)
+		if string(data) != tc.Data {
+			t.Errorf("unexpected content for %s: %q", tc.File, data)
+		}
+	}
+
+	_, err = ParseTemplateSpec([]byte("name: x\nprompts: [{key: A, message: a}]\nfiles: [{pattern: '*.sh', mode: rwx}]\n"))
+	if err == nil || !strings.Contains(err.Error(), "bad file mode") {
+		t.Errorf("expected a bad file mode error, got: %v", err)
+	}
+}
+
+func TestTmplWriter_Renames(t *testing.T) {
+	templFs := fstest.MapFS{
+		TemplateSpecFileName: {Data: []byte(`
+name: renames
+prompts:
+  - key: ProjectName
+    message: name
+rename:
+  dockerignore: .dockerignore
+  web/assets: static
+  web/assets/raw: raw
+files:
+  - pattern: tools/go.mod
+    when: .Tools
+`)},
+		"{{.ProjectName}}/main.go_":             {Data: []byte("//go:build exclude\n// +build exclude {{.ProjectName}}\npackage main\n")},
+		"{{.ProjectName}}/go.mod_":              {Data: []byte("module {{.ProjectName}}\n")},
+		"{{.ProjectName}}/api/v1/go.mod_":       {Data: []byte("module {{.ProjectName}}/api/v1\n")},
+		"{{.ProjectName}}/tools/go.mod_":        {Data: []byte("module tools\n")},
+		"{{.ProjectName}}/.gitignore_":          {Data: []byte("/dist\n")},
+		"{{.ProjectName}}/keep__":               {Data: []byte("underscore\n")},
+		"{{.ProjectName}}/dockerignore":         {Data: []byte("*\n")},
+		"{{.ProjectName}}/web/assets/app.js":    {Data: []byte("app\n")},
+		"{{.ProjectName}}/web/assets/raw/a.txt": {Data: []byte("raw\n")},
+		"{{.ProjectName}}/{{.Dir}}/b.txt":       {Data: []byte("value\n")},
+	}
+
+	afs := afero.NewMemMapFs()
+	w, err := NewTmplWriterFromFs(afs, templFs, ".", "renames", map[string]any{"ProjectName": "rn", "Tools": "false", "Dir": "vendor_"})
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+
+	err = w.BuildProject("/out")
+	if err != nil {
+		t.Fatalf("failed to build project: %v", err)
+	}
+
+	for file, want := range map[string]string{
+		"main.go":       "package main\n",
+		"go.mod":        "module rn\n",
+		"api/v1/go.mod": "module rn/api/v1\n",
+		".gitignore":    "/dist\n",
+		"keep_":         "underscore\n",
+		".dockerignore": "*\n",
+		"static/app.js": "app\n",
+		"raw/a.txt":     "raw\n",
+		"vendor_/b.txt": "value\n",
+	} {
+		data, readErr := afero.ReadFile(afs, filepath.Join("/out/rn", file))
+		if readErr != nil {
+			t.Errorf("expected %s to be generated: %v", file, readErr)
+			continue
+		}
+		if string(data) != want {
+			t.Errorf("unexpected content for %s: %q", file, data)
+		}
+	}
+
+	for _, file := range []string{"main.go_", "go.mod_", "dockerignore", "web/assets", "tools/go.mod"} {
+		exists, _ := afero.Exists(afs, filepath.Join("/out/rn", file))
+		if exists {
+			t.Errorf("expected %s not to be generated", file)
+		}
+	}
+
+	_, err = ParseTemplateSpec([]byte("name: x\nprompts: [{key: A, message: a}]\nrename: {a: ../b}\n"))
+	if err == nil || !strings.Contains(err.Error(), "not a path relative to the project root") {
+		t.Errorf("expected a bad rename error, got: %v", err)
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	for _, tc := range []struct {
+		Templ    string
+		Resolved string
+		Want     string
+	}{
+		{Templ: "{{.ProjectName}}/go.mod_", Resolved: "proj/go.mod_", Want: "proj/go.mod"},
+		{Templ: "{{.ProjectName}}/keep__", Resolved: "proj/keep__", Want: "proj/keep_"},
+		{Templ: "{{.ProjectName}}/main.go", Resolved: "proj_/main.go", Want: "proj_/main.go"},
+		{Templ: "{{.ProjectName}}_/main.go", Resolved: "proj_/main.go", Want: "proj/main.go"},
+		{Templ: "_/a", Resolved: "_/a", Want: "_/a"},
+		// A value spanning elements: only the static ones either side are matched.
+		{Templ: "dir_/{{.Pkg}}/x_", Resolved: "dir_/a_/b_/x_", Want: "dir/a_/b_/x"},
+	} {
+		if got := outputPath(tc.Templ, tc.Resolved); got != tc.Want {
+			t.Errorf("outputPath(%q, %q) = %q, want %q", tc.Templ, tc.Resolved, got, tc.Want)
+		}
+	}
+}